@@ -0,0 +1,93 @@
+// Package apikey provides types and functions for api key authentication and usage accounting.
+//
+// The api keys are defined in a JSON file and the usage counters of each key are kept
+// in a local embedded bbolt database so that they survive the server restarts.
+package apikey
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/cod3rboy/yaps/utils/sliceutils"
+)
+
+// Wildcard value which allows any origin in [Key.AllowedOrigins]
+const anyOrigin = "*"
+
+// A Key represents an api key along with its limits.
+//
+// Zero or empty value of a limit means that the limit is not applied.
+type Key struct {
+	Key            string   `json:"key"`            // Secret key sent by the client
+	Name           string   `json:"name"`           // Name of the key owner, used for usage accounting
+	AllowedFormats []string `json:"allowedFormats"` // Image formats which can be rendered
+	MaxWidth       int      `json:"maxWidth"`       // Maximum width of the rendered image
+	MaxHeight      int      `json:"maxHeight"`      // Maximum height of the rendered image
+	DailyQuota     int      `json:"dailyQuota"`     // Maximum number of renders per day
+	AllowedOrigins []string `json:"allowedOrigins"` // Origins from which the key can be used
+}
+
+// AllowsFormat returns true if the image format can be rendered with the key otherwise it returns false.
+func (k *Key) AllowsFormat(format string) bool {
+	return len(k.AllowedFormats) == 0 || sliceutils.ContainsString(k.AllowedFormats, format)
+}
+
+// AllowsSize returns true if an image of given width and height can be rendered with the key otherwise it returns false.
+func (k *Key) AllowsSize(width, height int) bool {
+	return (k.MaxWidth <= 0 || width <= k.MaxWidth) && (k.MaxHeight <= 0 || height <= k.MaxHeight)
+}
+
+// AllowsOrigin returns true if the key can be used from the given origin otherwise it returns false.
+//
+// Requests without an origin e.g. server-side requests are always allowed.
+func (k *Key) AllowsOrigin(origin string) bool {
+	if origin == "" || len(k.AllowedOrigins) == 0 {
+		return true
+	}
+	for _, allowed := range k.AllowedOrigins {
+		if allowed == anyOrigin || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// A Registry maps the secret keys to their [Key] definitions.
+type Registry map[string]*Key
+
+// Lookup returns the [Key] for given secret key and true if it exists otherwise it returns nil, false.
+func (r Registry) Lookup(key string) (*Key, bool) {
+	k, exists := r[key]
+	return k, exists
+}
+
+// LoadRegistry reads the api keys from the JSON file at path.
+//
+// The file must contain an array of keys e.g.
+//
+//	[{"key": "abcd1234", "name": "team-web", "allowedFormats": ["png", "webp"], "maxWidth": 2000, "maxHeight": 2000, "dailyQuota": 10000, "allowedOrigins": ["https://example.com"]}]
+//
+// If an error occurs while reading or parsing the file, it returns nil, error.
+func LoadRegistry(path string) (Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var keys []*Key
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, err
+	}
+	registry := make(Registry, len(keys))
+	for _, k := range keys {
+		if k.Key == "" || k.Name == "" {
+			return nil, errors.New("api key must have both key and name")
+		}
+		if _, exists := registry[k.Key]; exists {
+			return nil, errors.New("duplicate api key for " + k.Name)
+		}
+		registry[k.Key] = k
+	}
+	return registry, nil
+}
@@ -0,0 +1,100 @@
+package apikey
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeyAllowsFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    Key
+		format string
+		want   bool
+	}{
+		{name: "No restriction", key: Key{}, format: "png", want: true},
+		{name: "Allowed format", key: Key{AllowedFormats: []string{"png", "webp"}}, format: "webp", want: true},
+		{name: "Disallowed format", key: Key{AllowedFormats: []string{"png", "webp"}}, format: "tiff", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.key.AllowsFormat(tt.format); got != tt.want {
+				t.Errorf("AllowsFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyAllowsSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		key           Key
+		width, height int
+		want          bool
+	}{
+		{name: "No restriction", key: Key{}, width: 5000, height: 5000, want: true},
+		{name: "Within limits", key: Key{MaxWidth: 1000, MaxHeight: 500}, width: 1000, height: 500, want: true},
+		{name: "Width exceeded", key: Key{MaxWidth: 1000, MaxHeight: 500}, width: 1001, height: 500, want: false},
+		{name: "Height exceeded", key: Key{MaxWidth: 1000, MaxHeight: 500}, width: 100, height: 501, want: false},
+		{name: "Only width limited", key: Key{MaxWidth: 1000}, width: 100, height: 5000, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.key.AllowsSize(tt.width, tt.height); got != tt.want {
+				t.Errorf("AllowsSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyAllowsOrigin(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    Key
+		origin string
+		want   bool
+	}{
+		{name: "No restriction", key: Key{}, origin: "https://foo.com", want: true},
+		{name: "No origin", key: Key{AllowedOrigins: []string{"https://example.com"}}, origin: "", want: true},
+		{name: "Allowed origin", key: Key{AllowedOrigins: []string{"https://example.com"}}, origin: "https://EXAMPLE.com", want: true},
+		{name: "Wildcard origin", key: Key{AllowedOrigins: []string{"*"}}, origin: "https://foo.com", want: true},
+		{name: "Disallowed origin", key: Key{AllowedOrigins: []string{"https://example.com"}}, origin: "https://foo.com", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.key.AllowsOrigin(tt.origin); got != tt.want {
+				t.Errorf("AllowsOrigin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadRegistry(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantLen int
+		wantErr bool
+	}{
+		{name: "Valid keys", content: `[{"key": "k1", "name": "team-a"}, {"key": "k2", "name": "team-b", "dailyQuota": 10}]`, wantLen: 2, wantErr: false},
+		{name: "Missing name", content: `[{"key": "k1"}]`, wantErr: true},
+		{name: "Duplicate key", content: `[{"key": "k1", "name": "team-a"}, {"key": "k1", "name": "team-b"}]`, wantErr: true},
+		{name: "Invalid JSON", content: `{`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "keys.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			registry, err := LoadRegistry(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadRegistry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(registry) != tt.wantLen {
+				t.Errorf("LoadRegistry() len = %d, want %d", len(registry), tt.wantLen)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package apikey
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Name of the bbolt bucket which stores usage counters
+var usageBucket = []byte("usage")
+
+// Layout of the day used in usage counter keys
+const dayLayout = "2006-01-02"
+
+// ErrQuotaExceeded is returned by [Store.Increment] when the daily quota of a key is used up.
+var ErrQuotaExceeded = errors.New("daily quota exceeded")
+
+// A Usage represents the number of renders of a key on a day.
+type Usage struct {
+	Name    string            `json:"name"`    // Name of the key owner
+	Day     string            `json:"day"`     // Day in format YYYY-MM-DD
+	Total   uint64            `json:"total"`   // Total renders on the day
+	Formats map[string]uint64 `json:"formats"` // Renders on the day per image format
+}
+
+// A Store keeps the usage counters of api keys in a bbolt database file.
+type Store struct {
+	db *bolt.DB
+}
+
+// OpenStore opens the bbolt database at path, creating it if it does not exist.
+//
+// If an error occurs while opening the database, it returns nil, error.
+func OpenStore(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(usageBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the underlying database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Increment records a render of given image format with the key on the day of t.
+//
+// If the key has a daily quota and it is already used up, nothing is recorded and it returns [ErrQuotaExceeded].
+func (s *Store) Increment(key *Key, format string, t time.Time) error {
+	day := t.UTC().Format(dayLayout)
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(usageBucket)
+		totalKey := counterKey(key.Name, day, "")
+		total := readCounter(bucket, totalKey)
+		if key.DailyQuota > 0 && total >= uint64(key.DailyQuota) {
+			return ErrQuotaExceeded
+		}
+		formatKey := counterKey(key.Name, day, format)
+		if err := writeCounter(bucket, totalKey, total+1); err != nil {
+			return err
+		}
+		return writeCounter(bucket, formatKey, readCounter(bucket, formatKey)+1)
+	})
+}
+
+// Decrement removes a render of given image format with the key on the day of t recorded by [Store.Increment],
+// e.g. when the render fails after it is recorded. Counters which are already 0 are left as they are.
+func (s *Store) Decrement(key *Key, format string, t time.Time) error {
+	day := t.UTC().Format(dayLayout)
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(usageBucket)
+		for _, counter := range [][]byte{counterKey(key.Name, day, ""), counterKey(key.Name, day, format)} {
+			if value := readCounter(bucket, counter); value > 0 {
+				if err := writeCounter(bucket, counter, value-1); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Usage returns the usage of the key named name on the day of t.
+func (s *Store) Usage(name string, t time.Time) (*Usage, error) {
+	day := t.UTC().Format(dayLayout)
+	usage := &Usage{Name: name, Day: day, Formats: map[string]uint64{}}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(usageBucket)
+		prefix := counterKey(name, day, "")
+		usage.Total = readCounter(bucket, prefix)
+		cursor := bucket.Cursor()
+		prefix = append(prefix, '/')
+		for k, v := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
+			usage.Formats[string(k[len(prefix):])] = binary.BigEndian.Uint64(v)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// counterKey returns the bucket key of a counter in format <name>/<day> or <name>/<day>/<format>.
+func counterKey(name, day, format string) []byte {
+	key := name + "/" + day
+	if format != "" {
+		key += "/" + format
+	}
+	return []byte(key)
+}
+
+// readCounter returns the counter value stored at key in bucket, or 0 if it does not exist.
+func readCounter(bucket *bolt.Bucket, key []byte) uint64 {
+	value := bucket.Get(key)
+	if len(value) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(value)
+}
+
+// writeCounter stores the counter value at key in bucket.
+func writeCounter(bucket *bolt.Bucket, key []byte, value uint64) error {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, value)
+	return bucket.Put(key, buf)
+}
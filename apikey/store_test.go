@@ -0,0 +1,81 @@
+package apikey
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreIncrement(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.db")
+	store, err := OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := &Key{Key: "k1", Name: "team-a", DailyQuota: 3}
+	today := time.Date(2022, 9, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, format := range []string{"png", "png", "webp"} {
+		if err := store.Increment(key, format, today); err != nil {
+			t.Fatalf("Increment() error = %v", err)
+		}
+	}
+	if err := store.Increment(key, "png", today); err != ErrQuotaExceeded {
+		t.Fatalf("Increment() error = %v, want %v", err, ErrQuotaExceeded)
+	}
+	// Quota resets on the next day
+	if err := store.Increment(key, "png", today.Add(24*time.Hour)); err != nil {
+		t.Fatalf("Increment() error = %v", err)
+	}
+
+	// Counters survive reopening the store
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	store, err = OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	usage, err := store.Usage(key.Name, today)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.Day != "2022-09-01" || usage.Total != 3 || usage.Formats["png"] != 2 || usage.Formats["webp"] != 1 {
+		t.Errorf("Usage() = %+v, want day = 2022-09-01, total = 3, png = 2, webp = 1", usage)
+	}
+}
+
+func TestStoreDecrement(t *testing.T) {
+	store, err := OpenStore(filepath.Join(t.TempDir(), "usage.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	key := &Key{Key: "k1", Name: "team-a", DailyQuota: 1}
+	today := time.Date(2022, 9, 1, 10, 0, 0, 0, time.UTC)
+
+	if err := store.Increment(key, "png", today); err != nil {
+		t.Fatalf("Increment() error = %v", err)
+	}
+	if err := store.Decrement(key, "png", today); err != nil {
+		t.Fatalf("Decrement() error = %v", err)
+	}
+	// Refunded render frees the quota again
+	if err := store.Increment(key, "webp", today); err != nil {
+		t.Fatalf("Increment() error = %v", err)
+	}
+	// Counters do not go below 0
+	if err := store.Decrement(key, "png", today); err != nil {
+		t.Fatalf("Decrement() error = %v", err)
+	}
+
+	usage, err := store.Usage("team-a", today)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.Total != 0 || usage.Formats["png"] != 0 || usage.Formats["webp"] != 1 {
+		t.Errorf("usage = %+v, want total = 0, png = 0, webp = 1", usage)
+	}
+}
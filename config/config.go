@@ -17,14 +17,40 @@ const defaultHostPort = 8080
 const defaultAllowOrigins = "*"
 const defaultAllowMethods = "GET,POST,PUT,PATCH,DELETE"
 const defaultPathPrefix = "/"
+const defaultSignSecret = ""
+const defaultAPIKeysFile = ""
+const defaultUsageStore = "yaps.db"
+const defaultFontsDir = ""
+const defaultFallbackFonts = ""
+const defaultPresetsFile = ""
+const defaultAvatarPalette = ""
+const defaultRandomPalette = ""
+const defaultContrastLevel = "AA"
+const defaultPhotosDir = ""
+const defaultAssetsDir = ""
+const defaultLabelFormat = "{w} x {h}"
+const defaultTemplatesDir = ""
 
 // Configuration variables for application
 var (
-	hostName     = flag.String("hostName", defaultHostName, "Server host name")
-	hostPort     = flag.Int("hostPort", defaultHostPort, "Server port number")
-	pathPrefix   = flag.String("pathPrefix", defaultPathPrefix, "Prefix path for all routes")
-	allowOrigins = flag.String("allowOrigins", defaultAllowOrigins, "List of allowed origins")
-	allowMethods = flag.String("allowMethods", defaultAllowMethods, "List of allowed http methods")
+	hostName      = flag.String("hostName", defaultHostName, "Server host name")
+	hostPort      = flag.Int("hostPort", defaultHostPort, "Server port number")
+	pathPrefix    = flag.String("pathPrefix", defaultPathPrefix, "Prefix path for all routes")
+	allowOrigins  = flag.String("allowOrigins", defaultAllowOrigins, "List of allowed origins")
+	allowMethods  = flag.String("allowMethods", defaultAllowMethods, "List of allowed http methods")
+	signSecret    = flag.String("signSecret", defaultSignSecret, "Secret key to verify signed urls (Signing is disabled when empty)")
+	apiKeysFile   = flag.String("apiKeysFile", defaultAPIKeysFile, "Path to JSON file of api keys (Api key authentication is disabled when empty)")
+	usageStore    = flag.String("usageStore", defaultUsageStore, "Path to database file which stores api key usage")
+	fontsDir      = flag.String("fontsDir", defaultFontsDir, "Path to directory of TrueType and OpenType fonts to load")
+	fallbackFonts = flag.String("fallbackFonts", defaultFallbackFonts, "Comma-separated font names to look up missing glyphs in")
+	presetsFile   = flag.String("presetsFile", defaultPresetsFile, "Path to file of named image presets")
+	avatarPalette = flag.String("avatarPalette", defaultAvatarPalette, "Comma-separated hexadecimal colors for avatar backgrounds")
+	randomPalette = flag.String("randomPalette", defaultRandomPalette, "Comma-separated hexadecimal colors for random backgrounds")
+	contrastLevel = flag.String("contrastLevel", defaultContrastLevel, "WCAG contrast level (AA or AAA) of automatic text color")
+	photosDir     = flag.String("photosDir", defaultPhotosDir, "Path to directory of photos tagged by subdirectory")
+	assetsDir     = flag.String("assetsDir", defaultAssetsDir, "Path to directory of PNG and SVG overlay assets")
+	labelFormat   = flag.String("labelFormat", defaultLabelFormat, "Template of the default image text e.g. {w} x {h}")
+	templatesDir  = flag.String("templatesDir", defaultTemplatesDir, "Path to directory of JSON and YAML image templates")
 )
 
 // Load parses the command-line flags
@@ -57,3 +83,88 @@ func AllowOrigins() string {
 func AllowMethods() string {
 	return *allowMethods
 }
+
+// SignSecret returns configured secret key to verify signed urls.
+//
+// An empty secret means url signing is disabled.
+func SignSecret() string {
+	return *signSecret
+}
+
+// APIKeysFile returns configured path to the JSON file of api keys.
+//
+// An empty path means api key authentication is disabled.
+func APIKeysFile() string {
+	return *apiKeysFile
+}
+
+// UsageStore returns configured path to the database file which stores api key usage.
+func UsageStore() string {
+	return *usageStore
+}
+
+// FontsDir returns configured path to the directory of TrueType and OpenType fonts.
+//
+// An empty path means only built-in fonts are available.
+func FontsDir() string {
+	return *fontsDir
+}
+
+// FallbackFonts returns comma-separated list of configured font names in which missing glyphs are looked up in order.
+//
+// An empty list means missing glyphs are drawn as the missing glyph box of the font.
+func FallbackFonts() string {
+	return *fallbackFonts
+}
+
+// PresetsFile returns configured path to the file of named image presets.
+//
+// An empty path means only built-in default preset is available.
+func PresetsFile() string {
+	return *presetsFile
+}
+
+// AvatarPalette returns comma-separated list of configured avatar background colors.
+//
+// An empty list means the built-in palette is used.
+func AvatarPalette() string {
+	return *avatarPalette
+}
+
+// RandomPalette returns comma-separated list of configured random background colors.
+//
+// An empty list means random background colors are generated by color scheme.
+func RandomPalette() string {
+	return *randomPalette
+}
+
+// ContrastLevel returns configured WCAG contrast level of automatic text color, either AA or AAA.
+func ContrastLevel() string {
+	return *contrastLevel
+}
+
+// PhotosDir returns configured path to the directory of photos, tagged by subdirectory.
+//
+// An empty path means photos are disabled.
+func PhotosDir() string {
+	return *photosDir
+}
+
+// AssetsDir returns configured path to the directory of PNG and SVG overlay assets.
+//
+// An empty path means overlays are disabled.
+func AssetsDir() string {
+	return *assetsDir
+}
+
+// LabelFormat returns configured template of the default image text, with variables such as {w} and {h}.
+func LabelFormat() string {
+	return *labelFormat
+}
+
+// TemplatesDir returns configured path to the directory of JSON and YAML image templates.
+//
+// An empty path means templates are disabled.
+func TemplatesDir() string {
+	return *templatesDir
+}
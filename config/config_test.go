@@ -96,3 +96,237 @@ func TestAllowMethods(t *testing.T) {
 		}
 	}
 }
+
+var testSignSecretData = []TestData{
+	{FlagArg: "", Expected: defaultSignSecret},
+	{FlagArg: "s3cr3t", Expected: "s3cr3t"},
+}
+
+func TestSignSecret(t *testing.T) {
+	LoadFlags()
+	for _, data := range testSignSecretData {
+		if data.FlagArg != "" {
+			flag.Set("signSecret", data.FlagArg)
+		}
+		actual := SignSecret()
+		if actual != data.Expected {
+			t.Errorf("expected = %s, actual = %s\n", data.Expected, actual)
+		}
+	}
+}
+
+var testAPIKeysFileData = []TestData{
+	{FlagArg: "", Expected: defaultAPIKeysFile},
+	{FlagArg: "keys.json", Expected: "keys.json"},
+}
+
+func TestAPIKeysFile(t *testing.T) {
+	LoadFlags()
+	for _, data := range testAPIKeysFileData {
+		if data.FlagArg != "" {
+			flag.Set("apiKeysFile", data.FlagArg)
+		}
+		actual := APIKeysFile()
+		if actual != data.Expected {
+			t.Errorf("expected = %s, actual = %s\n", data.Expected, actual)
+		}
+	}
+}
+
+var testUsageStoreData = []TestData{
+	{FlagArg: "", Expected: defaultUsageStore},
+	{FlagArg: "/var/lib/yaps/usage.db", Expected: "/var/lib/yaps/usage.db"},
+}
+
+func TestUsageStore(t *testing.T) {
+	LoadFlags()
+	for _, data := range testUsageStoreData {
+		if data.FlagArg != "" {
+			flag.Set("usageStore", data.FlagArg)
+		}
+		actual := UsageStore()
+		if actual != data.Expected {
+			t.Errorf("expected = %s, actual = %s\n", data.Expected, actual)
+		}
+	}
+}
+
+var testFontsDirData = []TestData{
+	{FlagArg: "", Expected: defaultFontsDir},
+	{FlagArg: "/usr/share/fonts/truetype", Expected: "/usr/share/fonts/truetype"},
+}
+
+func TestFontsDir(t *testing.T) {
+	LoadFlags()
+	for _, data := range testFontsDirData {
+		if data.FlagArg != "" {
+			flag.Set("fontsDir", data.FlagArg)
+		}
+		actual := FontsDir()
+		if actual != data.Expected {
+			t.Errorf("expected = %s, actual = %s\n", data.Expected, actual)
+		}
+	}
+}
+
+var testPresetsFileData = []TestData{
+	{FlagArg: "", Expected: defaultPresetsFile},
+	{FlagArg: "presets.ini", Expected: "presets.ini"},
+}
+
+func TestPresetsFile(t *testing.T) {
+	LoadFlags()
+	for _, data := range testPresetsFileData {
+		if data.FlagArg != "" {
+			flag.Set("presetsFile", data.FlagArg)
+		}
+		actual := PresetsFile()
+		if actual != data.Expected {
+			t.Errorf("expected = %s, actual = %s\n", data.Expected, actual)
+		}
+	}
+}
+
+var testAvatarPaletteData = []TestData{
+	{FlagArg: "", Expected: defaultAvatarPalette},
+	{FlagArg: "EF4444,3B82F6", Expected: "EF4444,3B82F6"},
+}
+
+func TestAvatarPalette(t *testing.T) {
+	LoadFlags()
+	for _, data := range testAvatarPaletteData {
+		if data.FlagArg != "" {
+			flag.Set("avatarPalette", data.FlagArg)
+		}
+		actual := AvatarPalette()
+		if actual != data.Expected {
+			t.Errorf("expected = %s, actual = %s\n", data.Expected, actual)
+		}
+	}
+}
+
+var testRandomPaletteData = []TestData{
+	{FlagArg: "", Expected: defaultRandomPalette},
+	{FlagArg: "F87171,60A5FA", Expected: "F87171,60A5FA"},
+}
+
+func TestRandomPalette(t *testing.T) {
+	LoadFlags()
+	for _, data := range testRandomPaletteData {
+		if data.FlagArg != "" {
+			flag.Set("randomPalette", data.FlagArg)
+		}
+		actual := RandomPalette()
+		if actual != data.Expected {
+			t.Errorf("expected = %s, actual = %s\n", data.Expected, actual)
+		}
+	}
+}
+
+var testContrastLevelData = []TestData{
+	{FlagArg: "", Expected: defaultContrastLevel},
+	{FlagArg: "AAA", Expected: "AAA"},
+}
+
+func TestContrastLevel(t *testing.T) {
+	LoadFlags()
+	for _, data := range testContrastLevelData {
+		if data.FlagArg != "" {
+			flag.Set("contrastLevel", data.FlagArg)
+		}
+		actual := ContrastLevel()
+		if actual != data.Expected {
+			t.Errorf("expected = %s, actual = %s\n", data.Expected, actual)
+		}
+	}
+}
+
+var testPhotosDirData = []TestData{
+	{FlagArg: "", Expected: defaultPhotosDir},
+	{FlagArg: "photos", Expected: "photos"},
+}
+
+func TestPhotosDir(t *testing.T) {
+	LoadFlags()
+	for _, data := range testPhotosDirData {
+		if data.FlagArg != "" {
+			flag.Set("photosDir", data.FlagArg)
+		}
+		actual := PhotosDir()
+		if actual != data.Expected {
+			t.Errorf("expected = %s, actual = %s\n", data.Expected, actual)
+		}
+	}
+}
+
+var testFallbackFontsData = []TestData{
+	{FlagArg: "", Expected: defaultFallbackFonts},
+	{FlagArg: "NotoSansCJKsc-Regular,NotoColorEmoji", Expected: "NotoSansCJKsc-Regular,NotoColorEmoji"},
+}
+
+func TestFallbackFonts(t *testing.T) {
+	LoadFlags()
+	for _, data := range testFallbackFontsData {
+		if data.FlagArg != "" {
+			flag.Set("fallbackFonts", data.FlagArg)
+		}
+		actual := FallbackFonts()
+		if actual != data.Expected {
+			t.Errorf("expected = %s, actual = %s\n", data.Expected, actual)
+		}
+	}
+}
+
+var testAssetsDirData = []TestData{
+	{FlagArg: "", Expected: defaultAssetsDir},
+	{FlagArg: "assets", Expected: "assets"},
+}
+
+func TestAssetsDir(t *testing.T) {
+	LoadFlags()
+	for _, data := range testAssetsDirData {
+		if data.FlagArg != "" {
+			flag.Set("assetsDir", data.FlagArg)
+		}
+		actual := AssetsDir()
+		if actual != data.Expected {
+			t.Errorf("expected = %s, actual = %s\n", data.Expected, actual)
+		}
+	}
+}
+
+var testLabelFormatData = []TestData{
+	{FlagArg: "", Expected: defaultLabelFormat},
+	{FlagArg: "{w}x{h} @{scale}x", Expected: "{w}x{h} @{scale}x"},
+}
+
+func TestLabelFormat(t *testing.T) {
+	LoadFlags()
+	for _, data := range testLabelFormatData {
+		if data.FlagArg != "" {
+			flag.Set("labelFormat", data.FlagArg)
+		}
+		actual := LabelFormat()
+		if actual != data.Expected {
+			t.Errorf("expected = %s, actual = %s\n", data.Expected, actual)
+		}
+	}
+}
+
+var testTemplatesDirData = []TestData{
+	{FlagArg: "", Expected: defaultTemplatesDir},
+	{FlagArg: "templates", Expected: "templates"},
+}
+
+func TestTemplatesDir(t *testing.T) {
+	LoadFlags()
+	for _, data := range testTemplatesDirData {
+		if data.FlagArg != "" {
+			flag.Set("templatesDir", data.FlagArg)
+		}
+		actual := TemplatesDir()
+		if actual != data.Expected {
+			t.Errorf("expected = %s, actual = %s\n", data.Expected, actual)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package img
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/cod3rboy/yaps/utils"
+	"github.com/fogleman/gg"
+)
+
+// Constants for avatar shapes
+const (
+	SHAPE_CIRCLE  = "circle"
+	SHAPE_ROUNDED = "rounded"
+	SHAPE_SQUARE  = "square"
+)
+
+// Corner radius of rounded avatar as a fraction of its side
+const roundedCornerRadius = 0.2
+
+// Initials font size as a fraction of avatar side
+const initialsFontSize = 0.4
+
+// Color used for transparent areas in formats without transparency (White)
+var matteColor = White
+
+// An AvatarParams stores parameters for avatar generation.
+type AvatarParams struct {
+	Format          string  // Image extension
+	*Size                   // Image Size
+	Shape           string  // Avatar shape, one of SHAPE_CIRCLE, SHAPE_ROUNDED or SHAPE_SQUARE
+	BackgroundColor *Color  // Color to fill the shape
+	TextColor       *Color  // Color to use for initials
+	Scale           float64 // Value by which to scale Size
+	Initials        string  // Initials to write on the avatar
+	Font            string  // Name of the font to write initials, see [LoadFonts]
+}
+
+// GenerateAvatar generates an avatar image with initials written inside a shape.
+//
+// The shape is centered and sized to the smaller dimension of the image. The area outside the shape is
+// transparent for formats which support transparency, otherwise it is filled with white color.
+//
+// It returns [ImageResult], nil when image is generated successfully.
+// If error occurs while generating image, it returns nil, error.
+func GenerateAvatar(params *AvatarParams) (*ImageResult, error) {
+	w := utils.ScaleDimension(params.Width, params.Scale)
+	h := utils.ScaleDimension(params.Height, params.Scale)
+
+	font, err := getFont(params.Font)
+	if err != nil {
+		return nil, err
+	}
+
+	canvas := gg.NewContext(w, h)
+	if !SupportsTransparency(params.Format) {
+		FillBackground(canvas, &matteColor)
+	}
+
+	side := math.Min(float64(w), float64(h))
+	x, y := (float64(w)-side)/2, (float64(h)-side)/2
+	switch params.Shape {
+	case SHAPE_CIRCLE:
+		canvas.DrawCircle(float64(w)/2, float64(h)/2, side/2)
+	case SHAPE_ROUNDED:
+		canvas.DrawRoundedRectangle(x, y, side, side, side*roundedCornerRadius)
+	case SHAPE_SQUARE:
+		canvas.DrawRectangle(x, y, side, side)
+	default:
+		return nil, fmt.Errorf("unknown avatar shape %s", params.Shape)
+	}
+	canvas.SetRGBA255(int(params.BackgroundColor.R), int(params.BackgroundColor.G), int(params.BackgroundColor.B), 0xFF)
+	canvas.Fill()
+
+	drawText(canvas, params.Initials, params.TextColor, font, side*PX_TO_PT*initialsFontSize, side)
+
+	return encodeResult(canvas, params.Format)
+}
+
+// SupportsTransparency returns true if the image format can store transparent pixels otherwise it returns false.
+func SupportsTransparency(format string) bool {
+	return format != IMAGE_JPG && format != IMAGE_JPEG
+}
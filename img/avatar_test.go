@@ -0,0 +1,55 @@
+package img
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func TestGenerateAvatar(t *testing.T) {
+	background := Color{0x33, 0x41, 0x55}
+	tests := []struct {
+		shape             string
+		cornerTransparent bool
+	}{
+		{shape: SHAPE_CIRCLE, cornerTransparent: true},
+		{shape: SHAPE_ROUNDED, cornerTransparent: true},
+		{shape: SHAPE_SQUARE, cornerTransparent: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shape, func(t *testing.T) {
+			result, err := GenerateAvatar(&AvatarParams{
+				Format:          IMAGE_PNG,
+				Size:            &Size{64, 64},
+				Shape:           tt.shape,
+				BackgroundColor: &background,
+				TextColor:       &White,
+				Scale:           1,
+				Initials:        "JD",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := png.Decode(bytes.NewReader(result.Bytes))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decoded.Bounds() != image.Rect(0, 0, 64, 64) {
+				t.Fatalf("expected bounds = 64x64, actual bounds = %v", decoded.Bounds())
+			}
+			_, _, _, a := decoded.At(0, 0).RGBA()
+			if transparent := a == 0; transparent != tt.cornerTransparent {
+				t.Errorf("corner transparent = %v, want %v", transparent, tt.cornerTransparent)
+			}
+			r, g, b, _ := decoded.At(4, 32).RGBA()
+			if uint8(r>>8) != background.R || uint8(g>>8) != background.G || uint8(b>>8) != background.B {
+				t.Errorf("expected background color at shape edge, actual = (%d,%d,%d)", r>>8, g>>8, b>>8)
+			}
+		})
+	}
+
+	if _, err := GenerateAvatar(&AvatarParams{Format: IMAGE_PNG, Size: &Size{64, 64}, Shape: "star", BackgroundColor: &background, TextColor: &White, Scale: 1}); err == nil {
+		t.Error("expected error for unknown shape")
+	}
+}
@@ -0,0 +1,101 @@
+package img
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/fogleman/gg"
+)
+
+// Margin between the text blocks and the edges of their bounds as a fraction of the smaller bounds dimension
+const blockMargin = 0.06
+
+// Gap between stacked text blocks as a fraction of the line height of the upper block
+const blockGap = 0.4
+
+// A TextBlock stores parameters for one block of text among the several text blocks of an image,
+// e.g. a title, a subtitle and a caption.
+type TextBlock struct {
+	Text     string  // Text of the block
+	Size     float64 // Font size as a percentage of the image height, in range (0, 100]
+	Color    *Color  // Color to use for text
+	Font     string  // Name of the font to write text, see [LoadFonts]
+	Position string  // Position of the block, one of the POSITION constants
+	MaxLines int     // Maximum number of lines, the last one ending with an ellipsis, 0 for no limit
+}
+
+// A shapedBlock stores the lines of a text block shaped by its shaper.
+type shapedBlock struct {
+	block  TextBlock
+	shaper *textShaper
+	lines  []textLine
+}
+
+// height returns the height of the lines of the block.
+func (b *shapedBlock) height() float64 {
+	return b.shaper.blockHeight(len(b.lines), shapeLineSpacing)
+}
+
+// DrawTextBlocks draws the text blocks on the canvas inside bounds.
+//
+// Blocks at the same position are stacked in the given order, e.g. a subtitle after a title at the centre,
+// and the stack is placed at the position with a margin from the bounds edges like an overlay.
+// Each line is aligned to the side of the position, or centered for the positions in the middle column.
+// Text is wrapped at the width of bounds without the margins.
+// If the font of a block is not registered or a parameter is out of range, it returns an error.
+func DrawTextBlocks(canvas *gg.Context, blocks []TextBlock, bounds Rect) error {
+	margin := math.Min(bounds.Width, bounds.Height) * blockMargin
+	area := Rect{X: bounds.X + margin, Y: bounds.Y + margin, Width: bounds.Width - 2*margin, Height: bounds.Height - 2*margin}
+
+	stacks := map[string][]*shapedBlock{}
+	positions := []string{}
+	for _, block := range blocks {
+		if _, exists := positionAnchors[block.Position]; !exists {
+			return fmt.Errorf("unknown text block position %s", block.Position)
+		}
+		if !(block.Size > 0 && block.Size <= 100) {
+			return fmt.Errorf("text block size must be in range (0, 100]")
+		}
+		font, err := getFont(block.Font)
+		if err != nil {
+			return err
+		}
+		shaper := newTextShaper(font)
+		shaper.setSize(float64(canvas.Height()) * block.Size / 100 * PX_TO_PT)
+		shaper.maxLines = block.MaxLines
+		shaper.ellipsis = true
+		if _, exists := stacks[block.Position]; !exists {
+			positions = append(positions, block.Position)
+		}
+		stacks[block.Position] = append(stacks[block.Position], &shapedBlock{
+			block:  block,
+			shaper: shaper,
+			lines:  shaper.wrap(block.Text, area.Width),
+		})
+	}
+
+	for _, position := range positions {
+		stack := stacks[position]
+		height := 0.0
+		for i, shaped := range stack {
+			if i > 0 {
+				height += stack[i-1].shaper.height * blockGap
+			}
+			height += shaped.height()
+		}
+		anchor := positionAnchors[position]
+		y := area.Y + (area.Height-height)*anchor[1]
+		for i, shaped := range stack {
+			if i > 0 {
+				y += stack[i-1].shaper.height * blockGap
+			}
+			baseline := y + shaped.shaper.height
+			for _, line := range shaped.lines {
+				drawLine(canvas, line, shaped.block.Color, area.X+(area.Width-line.width)*anchor[0], baseline)
+				baseline += shaped.shaper.height * shapeLineSpacing
+			}
+			y += shaped.height()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package img
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/fogleman/gg"
+)
+
+func TestDrawTextBlocks(t *testing.T) {
+	bounds := Rect{Width: 400, Height: 200}
+	tests := []struct {
+		name   string
+		blocks []TextBlock
+		check  func(ink image.Rectangle) bool
+	}{
+		{
+			name:   "Top left block starts at the margin",
+			blocks: []TextBlock{{Text: "Title", Size: 15, Color: &Black, Position: POSITION_TOP_LEFT}},
+			check: func(ink image.Rectangle) bool {
+				return ink.Min.X >= 12 && ink.Min.X < 16 && ink.Min.Y >= 12 && ink.Max.Y < 100
+			},
+		},
+		{
+			name:   "Bottom right block ends at the margin",
+			blocks: []TextBlock{{Text: "Caption", Size: 8, Color: &Black, Position: POSITION_BOTTOM_RIGHT}},
+			check:  func(ink image.Rectangle) bool { return ink.Max.X <= 388 && ink.Max.X > 384 && ink.Min.Y > 150 },
+		},
+		{
+			name: "Blocks at the same position are stacked",
+			blocks: []TextBlock{
+				{Text: "Title", Size: 15, Color: &Black, Position: POSITION_CENTER},
+				{Text: "Subtitle", Size: 8, Color: &Black, Position: POSITION_CENTER},
+			},
+			check: func(ink image.Rectangle) bool {
+				return ink.Dy() > 30 && ink.Min.Y+ink.Max.Y > 190 && ink.Min.Y+ink.Max.Y < 210
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canvas := gg.NewContext(400, 200)
+			FillBackground(canvas, &White)
+			if err := DrawTextBlocks(canvas, tt.blocks, bounds); err != nil {
+				t.Fatal(err)
+			}
+			if ink := inkBounds(canvas); !tt.check(ink) {
+				t.Errorf("unexpected ink bounds %v", ink)
+			}
+		})
+	}
+
+	canvas := gg.NewContext(400, 200)
+	if err := DrawTextBlocks(canvas, []TextBlock{{Text: "Title", Size: 10, Color: &Black, Position: "middle"}}, bounds); err == nil {
+		t.Error("expected error for unknown position")
+	}
+	if err := DrawTextBlocks(canvas, []TextBlock{{Text: "Title", Size: 10, Color: &Black, Font: "Comic", Position: POSITION_TOP}}, bounds); err == nil {
+		t.Error("expected error for unknown font")
+	}
+	if err := DrawTextBlocks(canvas, []TextBlock{{Text: "Title", Size: math.NaN(), Color: &Black, Position: POSITION_TOP}}, bounds); err == nil {
+		t.Error("expected error for NaN size")
+	}
+}
@@ -0,0 +1,228 @@
+package img
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+)
+
+// Limits of BlurHash components in each direction
+const (
+	MinBlurHashComponents = 1
+	MaxBlurHashComponents = 9
+)
+
+// Characters of the base 83 encoding used by BlurHash
+const base83Chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// DecodeBlurHash decodes the BlurHash string into an image of given width and height.
+//
+// The punch value scales the contrast of the decoded colors, 1 keeps the original contrast.
+// If the hash is malformed, it returns nil, error.
+func DecodeBlurHash(hash string, width, height int, punch float64) (*image.NRGBA, error) {
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("blurhash image dimensions must be positive")
+	}
+	if len(hash) < 6 {
+		return nil, errors.New("blurhash must have at least 6 characters")
+	}
+	sizeFlag, err := decodeBase83(hash[:1])
+	if err != nil {
+		return nil, err
+	}
+	numX, numY := sizeFlag%9+1, sizeFlag/9+1
+	if len(hash) != 4+2*numX*numY {
+		return nil, fmt.Errorf("blurhash length must be %d for %dx%d components", 4+2*numX*numY, numX, numY)
+	}
+	quantisedMaxValue, err := decodeBase83(hash[1:2])
+	if err != nil {
+		return nil, err
+	}
+	maxValue := float64(quantisedMaxValue+1) / 166 * punch
+
+	colors := make([][3]float64, numX*numY)
+	for i := range colors {
+		var value int
+		if i == 0 {
+			value, err = decodeBase83(hash[2:6])
+		} else {
+			value, err = decodeBase83(hash[4+i*2 : 6+i*2])
+		}
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			colors[i] = [3]float64{srgbToLinear(value >> 16), srgbToLinear(value >> 8 & 0xFF), srgbToLinear(value & 0xFF)}
+		} else {
+			colors[i] = [3]float64{
+				signPow(float64(value/(19*19)-9)/9, 2) * maxValue,
+				signPow(float64(value/19%19-9)/9, 2) * maxValue,
+				signPow(float64(value%19-9)/9, 2) * maxValue,
+			}
+		}
+	}
+
+	// The cosine basis is separable, so each row first sums the vertical components
+	cosX := cosineTable(width, numX)
+	cosY := cosineTable(height, numY)
+	row := make([][3]float64, numX)
+	decoded := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for i := range row {
+			row[i] = [3]float64{}
+			for j := 0; j < numY; j++ {
+				basis := cosY[y*numY+j]
+				for c := range row[i] {
+					row[i][c] += colors[i+j*numX][c] * basis
+				}
+			}
+		}
+		for x := 0; x < width; x++ {
+			var pixel [3]float64
+			for i := range row {
+				basis := cosX[x*numX+i]
+				for c := range pixel {
+					pixel[c] += row[i][c] * basis
+				}
+			}
+			decoded.SetNRGBA(x, y, color.NRGBA{R: linearToSRGB(pixel[0]), G: linearToSRGB(pixel[1]), B: linearToSRGB(pixel[2]), A: 0xFF})
+		}
+	}
+	return decoded, nil
+}
+
+// EncodeBlurHash encodes the image into a BlurHash string with given number of components in each direction.
+//
+// If the number of components is not in range [MinBlurHashComponents, MaxBlurHashComponents], it returns "", error.
+func EncodeBlurHash(src image.Image, xComponents, yComponents int) (string, error) {
+	if xComponents < MinBlurHashComponents || xComponents > MaxBlurHashComponents ||
+		yComponents < MinBlurHashComponents || yComponents > MaxBlurHashComponents {
+		return "", fmt.Errorf("blurhash components must be in range [%d, %d]", MinBlurHashComponents, MaxBlurHashComponents)
+	}
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return "", errors.New("blurhash image must not be empty")
+	}
+
+	// Convert pixels to linear RGB once
+	linear := make([][3]float64, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			pixel := color.NRGBAModel.Convert(src.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			linear[x+y*width] = [3]float64{srgbToLinear(int(pixel.R)), srgbToLinear(int(pixel.G)), srgbToLinear(int(pixel.B))}
+		}
+	}
+
+	cosX := cosineTable(width, xComponents)
+	cosY := cosineTable(height, yComponents)
+	factors := make([][3]float64, xComponents*yComponents)
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+			normalisation := 2.0
+			if i == 0 && j == 0 {
+				normalisation = 1.0
+			}
+			var factor [3]float64
+			for y := 0; y < height; y++ {
+				for x := 0; x < width; x++ {
+					basis := cosX[x*xComponents+i] * cosY[y*yComponents+j]
+					for c := range factor {
+						factor[c] += basis * linear[x+y*width][c]
+					}
+				}
+			}
+			scale := normalisation / float64(width*height)
+			factors[i+j*xComponents] = [3]float64{factor[0] * scale, factor[1] * scale, factor[2] * scale}
+		}
+	}
+
+	hash := new(strings.Builder)
+	hash.WriteString(encodeBase83((xComponents-1)+(yComponents-1)*9, 1))
+
+	maxValue := 1.0
+	if len(factors) > 1 {
+		actualMaxValue := 0.0
+		for _, factor := range factors[1:] {
+			for _, value := range factor {
+				actualMaxValue = math.Max(actualMaxValue, math.Abs(value))
+			}
+		}
+		quantisedMaxValue := int(math.Max(0, math.Min(82, math.Floor(actualMaxValue*166-0.5))))
+		maxValue = float64(quantisedMaxValue+1) / 166
+		hash.WriteString(encodeBase83(quantisedMaxValue, 1))
+	} else {
+		hash.WriteString(encodeBase83(0, 1))
+	}
+
+	dc := factors[0]
+	hash.WriteString(encodeBase83(int(linearToSRGB(dc[0]))<<16+int(linearToSRGB(dc[1]))<<8+int(linearToSRGB(dc[2])), 4))
+	for _, factor := range factors[1:] {
+		quantise := func(value float64) int {
+			return int(math.Max(0, math.Min(18, math.Floor(signPow(value/maxValue, 0.5)*9+9.5))))
+		}
+		hash.WriteString(encodeBase83(quantise(factor[0])*19*19+quantise(factor[1])*19+quantise(factor[2]), 2))
+	}
+	return hash.String(), nil
+}
+
+// cosineTable returns cos(pi * p * i / size) for each pixel p in [0, size) and component i in [0, components),
+// indexed by p * components + i.
+func cosineTable(size, components int) []float64 {
+	table := make([]float64, size*components)
+	for p := 0; p < size; p++ {
+		for i := 0; i < components; i++ {
+			table[p*components+i] = math.Cos(math.Pi * float64(p) * float64(i) / float64(size))
+		}
+	}
+	return table
+}
+
+// decodeBase83 decodes the base 83 encoded string into an integer.
+func decodeBase83(value string) (int, error) {
+	decoded := 0
+	for _, char := range value {
+		digit := strings.IndexRune(base83Chars, char)
+		if digit < 0 {
+			return 0, fmt.Errorf("invalid base83 character %q", char)
+		}
+		decoded = decoded*83 + digit
+	}
+	return decoded, nil
+}
+
+// encodeBase83 encodes the integer into a base 83 string of given length.
+func encodeBase83(value, length int) string {
+	encoded := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		encoded[i] = base83Chars[value%83]
+		value /= 83
+	}
+	return string(encoded)
+}
+
+// srgbToLinear converts an 8 bit sRGB color component into linear RGB in range [0, 1].
+func srgbToLinear(value int) float64 {
+	v := float64(value) / 0xFF
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB converts a linear RGB color component into 8 bit sRGB, clamping it to range [0, 1].
+func linearToSRGB(value float64) uint8 {
+	v := math.Max(0, math.Min(1, value))
+	if v <= 0.0031308 {
+		return uint8(v*12.92*0xFF + 0.5)
+	}
+	return uint8((1.055*math.Pow(v, 1/2.4)-0.055)*0xFF + 0.5)
+}
+
+// signPow raises the magnitude of value to exp while keeping its sign.
+func signPow(value, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(value), exp), value)
+}
@@ -0,0 +1,81 @@
+package img
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestEncodeBlurHash(t *testing.T) {
+	solid := image.NewNRGBA(image.Rect(0, 0, 20, 10))
+	draw.Draw(solid, solid.Bounds(), image.NewUniform(color.NRGBA{R: 0xCC, G: 0xCC, B: 0xCC, A: 0xFF}), image.Point{}, draw.Src)
+
+	hash, err := EncodeBlurHash(solid, 4, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hash) != 28 || hash[:1] != "L" || hash[2:6] != encodeBase83(0xCCCCCC, 4) {
+		t.Errorf("expected 4x3 hash with average color CCCCCC, actual hash = %s", hash)
+	}
+	decoded, err := DecodeBlurHash(hash, 20, 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := decoded.NRGBAAt(10, 5); diff(actual.R, 0xCC) > 8 || diff(actual.G, 0xCC) > 8 || diff(actual.B, 0xCC) > 8 {
+		t.Errorf("expected color = CCCCCC, actual color = %v", actual)
+	}
+
+	for _, components := range [][2]int{{0, 3}, {4, 10}} {
+		if _, err := EncodeBlurHash(solid, components[0], components[1]); err == nil {
+			t.Errorf("expected error for %dx%d components", components[0], components[1])
+		}
+	}
+}
+
+func TestDecodeBlurHash(t *testing.T) {
+	decoded, err := DecodeBlurHash("LEHV6nWB2yk8pyo0adR*.7kCMdnj", 32, 24, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := decoded.Bounds().Size(); size.X != 32 || size.Y != 24 {
+		t.Errorf("expected size = 32x24, actual size = %dx%d", size.X, size.Y)
+	}
+
+	// Decoding the encoded gradient gives back similar colors
+	gradient := image.NewNRGBA(image.Rect(0, 0, 32, 32))
+	for x := 0; x < 32; x++ {
+		for y := 0; y < 32; y++ {
+			gradient.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 8), G: 0x80, B: 0xFF - uint8(x*8), A: 0xFF})
+		}
+	}
+	hash, err := EncodeBlurHash(gradient, 4, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err = DecodeBlurHash(hash, 32, 32, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, x := range []int{8, 16, 24} {
+		expected, actual := gradient.NRGBAAt(x, 16), decoded.NRGBAAt(x, 16)
+		if diff(expected.R, actual.R) > 24 || diff(expected.G, actual.G) > 24 || diff(expected.B, actual.B) > 24 {
+			t.Errorf("x = %d, expected color = %v, actual color = %v", x, expected, actual)
+		}
+	}
+}
+
+func TestDecodeBlurHashInvalid(t *testing.T) {
+	for _, hash := range []string{"", "L0", "LEHV6nWB2yk8pyo0adR*.7kCMdn", "LEHV6nWB2yk8pyo0adR*.7kCMd\"j"} {
+		if _, err := DecodeBlurHash(hash, 32, 32, 1); err == nil {
+			t.Errorf("expected error for hash %q", hash)
+		}
+	}
+}
+
+func diff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
@@ -0,0 +1,103 @@
+package img
+
+// Constants for share card themes
+const (
+	CARD_THEME_LIGHT    = "light"
+	CARD_THEME_DARK     = "dark"
+	CARD_THEME_GRADIENT = "gradient"
+)
+
+// Name of the font used for the title of share cards when no title font is given
+const DefaultCardTitleFont = "Go-Bold"
+
+// A cardTheme stores the hexadecimal colors of a share card.
+type cardTheme struct {
+	from, to string // Colors of the background gradient, from the top left to the bottom right
+	text     string // Color of the title
+	muted    string // Color of the subtitle and the author
+	accent   string // Color of the bar at the bottom
+}
+
+// Mapping of a share card theme to its colors
+var cardThemes = map[string]cardTheme{
+	CARD_THEME_LIGHT:    {from: "ffffff", to: "f1f5f9", text: "0f172a", muted: "475569", accent: "6366f1"},
+	CARD_THEME_DARK:     {from: "0f172a", to: "1e293b", text: "f8fafc", muted: "94a3b8", accent: "818cf8"},
+	CARD_THEME_GRADIENT: {from: "4338ca", to: "db2777", text: "ffffff", muted: "e0e7ff", accent: "fbbf24"},
+}
+
+// A CardParams stores parameters for share card generation e.g. Open Graph images.
+type CardParams struct {
+	Format    string  // Image extension
+	*Size             // Image Size
+	Scale     float64 // Value by which to scale Size
+	Theme     string  // Card theme, one of the CARD_THEME constants
+	Title     string  // Title of the card
+	Subtitle  string  // Text below the title, empty for none
+	Author    string  // Text at the bottom of the card, empty for none
+	Logo      string  // Asset at the top of the card, empty for none, see [LoadAssets]
+	TitleFont string  // Name of the font to write the title, see [LoadFonts]
+	Font      string  // Name of the font to write the subtitle and the author
+}
+
+// GenerateCard generates a share card with the title, subtitle, author and logo laid out on the theme background.
+//
+// The layout is proportional to the card height. The title is left aligned above the subtitle, and it shrinks
+// to fit in three lines down to a minimum size, below which it is clamped with an ellipsis.
+// The card is drawn by a [Template], so its layers behave like the layers of templates.
+//
+// It returns [ImageResult], nil when image is generated successfully.
+// If error occurs while generating image, it returns nil, error.
+func GenerateCard(params *CardParams) (*ImageResult, error) {
+	return GenerateTemplate(&TemplateParams{
+		Format:   params.Format,
+		Template: cardTemplate(params),
+		Scale:    params.Scale,
+	})
+}
+
+// cardTemplate returns the template which draws the share card.
+func cardTemplate(params *CardParams) *Template {
+	theme, exists := cardThemes[params.Theme]
+	if !exists {
+		theme = cardThemes[CARD_THEME_LIGHT]
+	}
+	w, h := float64(params.Width), float64(params.Height)
+	// Lengths are designed for a 630 pixels high Open Graph card
+	unit := h / 630
+	margin := 80 * unit
+
+	layers := []TemplateLayer{
+		{Type: LAYER_GRADIENT, From: theme.from, To: theme.to, Angle: 135},
+		{Type: LAYER_FILL, Y: h - 12*unit, Height: 12 * unit, Color: theme.accent},
+	}
+	top := margin
+	if params.Logo != "" {
+		layers = append(layers, TemplateLayer{
+			Type: LAYER_OVERLAY, Asset: params.Logo, X: margin, Y: margin, Width: w / 3, Height: 64 * unit, Align: "left",
+		})
+		top += 104 * unit
+	}
+	// Text blocks are stacked from the bottom so that the title sits right above the subtitle
+	bottom := h - margin
+	if params.Author != "" {
+		layers = append(layers, TemplateLayer{
+			Type: LAYER_TEXT, Text: params.Author, Font: params.Font, Size: 28 * unit, Color: theme.muted,
+			X: margin, Y: bottom - 36*unit, Width: w - 2*margin, Height: 36 * unit, VAlign: "bottom", MaxLines: 1,
+		})
+		bottom -= 84 * unit
+	}
+	if params.Subtitle != "" {
+		layers = append(layers, TemplateLayer{
+			Type: LAYER_TEXT, Text: params.Subtitle, Font: params.Font, Size: 34 * unit, Color: theme.muted,
+			X: margin, Y: bottom - 82*unit, Width: w - 2*margin, Height: 82 * unit, MaxLines: 2,
+		})
+		bottom -= 110 * unit
+	}
+	layers = append(layers, TemplateLayer{
+		Type: LAYER_TEXT, Text: params.Title, Font: params.TitleFont, Size: 72 * unit, MinSize: 44 * unit, Color: theme.text,
+		X: margin, Y: top, Width: w - 2*margin, Height: bottom - top, VAlign: "bottom", Spacing: 1.15,
+		MaxLines: 3, Overflow: OVERFLOW_SHRINK,
+	})
+
+	return &Template{Width: params.Width, Height: params.Height, Layers: layers}
+}
@@ -0,0 +1,59 @@
+package img
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+)
+
+func TestCardTemplate(t *testing.T) {
+	params := &CardParams{Size: &Size{Width: 1200, Height: 630}, Theme: CARD_THEME_DARK, Title: "Title"}
+	if layers := cardTemplate(params).Layers; len(layers) != 3 {
+		t.Errorf("layers = %d, want background, accent bar and title", len(layers))
+	}
+
+	params.Subtitle, params.Author, params.Logo = "Subtitle", "Author", "logo"
+	layers := cardTemplate(params).Layers
+	if len(layers) != 6 {
+		t.Fatalf("layers = %d, want 6", len(layers))
+	}
+	title := layers[len(layers)-1]
+	if title.Text != "Title" || title.Overflow != OVERFLOW_SHRINK || title.MaxLines != 3 {
+		t.Errorf("title layer = %+v, want shrinking title in 3 lines", title)
+	}
+	// Title box sits between the logo and the subtitle
+	subtitle := layers[len(layers)-2]
+	if title.Y < layers[2].Y+layers[2].Height || title.Y+title.Height > subtitle.Y {
+		t.Errorf("title box %v-%v overlaps the logo or the subtitle", title.Y, title.Y+title.Height)
+	}
+	for _, layer := range layers {
+		if err := layer.validate(); err != nil {
+			t.Errorf("invalid %s layer: %v", layer.Type, err)
+		}
+	}
+}
+
+func TestGenerateCard(t *testing.T) {
+	for _, theme := range []string{CARD_THEME_LIGHT, CARD_THEME_DARK, CARD_THEME_GRADIENT} {
+		result, err := GenerateCard(&CardParams{
+			Format:    IMAGE_PNG,
+			Size:      &Size{Width: 1200, Height: 628},
+			Scale:     0.5,
+			Theme:     theme,
+			Title:     "A long title which has to shrink to fit in three lines on the card, or else be clamped with an ellipsis",
+			Subtitle:  "Subtitle",
+			TitleFont: DefaultCardTitleFont,
+			Font:      DefaultFont,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := png.Decode(bytes.NewReader(result.Bytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size := decoded.Bounds().Size(); size.X != 600 || size.Y != 314 {
+			t.Errorf("%s card size = %v, want 600x314", theme, size)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package img
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+// Colors used for readable text, see [ReadableTextColor]
+var (
+	White = Color{R: 0xFF, G: 0xFF, B: 0xFF}
+	Black = Color{R: 0x00, G: 0x00, B: 0x00}
+)
+
+// ErrContrastRatio is returned when no text color has the required contrast ratio against a background color.
+var ErrContrastRatio = errors.New("no text color has the required contrast ratio against the background color")
+
+// Luminance returns the relative luminance of the color as defined by WCAG 2.x.
+//
+// The luminance ranges from 0.0 for black to 1.0 for white.
+func (c Color) Luminance() float64 {
+	linear := func(component uint8) float64 {
+		value := float64(component) / 0xFF
+		if value <= 0.03928 {
+			return value / 12.92
+		}
+		return math.Pow((value+0.055)/1.055, 2.4)
+	}
+	return 0.2126*linear(c.R) + 0.7152*linear(c.G) + 0.0722*linear(c.B)
+}
+
+// ContrastRatio returns the contrast ratio between the colors a and b as defined by WCAG 2.x.
+//
+// The contrast ratio ranges from 1.0 for same colors to 21.0 for black and white.
+func ContrastRatio(a, b Color) float64 {
+	lighter, darker := a.Luminance(), b.Luminance()
+	if darker > lighter {
+		lighter, darker = darker, lighter
+	}
+	return (lighter + 0.05) / (darker + 0.05)
+}
+
+// ReadableTextColor returns either [White] or [Black], whichever has the higher contrast ratio against background.
+//
+// The returned color always has a contrast ratio of at least 4.5 which meets WCAG AA level for normal text.
+func ReadableTextColor(background Color) Color {
+	if ContrastRatio(background, White) >= ContrastRatio(background, Black) {
+		return White
+	}
+	return Black
+}
+
+// HSL returns the color for given hue in degrees [0, 360), saturation and lightness in range [0, 1].
+func HSL(hue, saturation, lightness float64) Color {
+	hue = math.Mod(math.Mod(hue, 360)+360, 360)
+	chroma := (1 - math.Abs(2*lightness-1)) * saturation
+	x := chroma * (1 - math.Abs(math.Mod(hue/60, 2)-1))
+	m := lightness - chroma/2
+
+	var r, g, b float64
+	switch {
+	case hue < 60:
+		r, g, b = chroma, x, 0
+	case hue < 120:
+		r, g, b = x, chroma, 0
+	case hue < 180:
+		r, g, b = 0, chroma, x
+	case hue < 240:
+		r, g, b = 0, x, chroma
+	case hue < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+	component := func(value float64) uint8 {
+		return uint8(math.Round((value + m) * 0xFF))
+	}
+	return Color{R: component(r), G: component(g), B: component(b)}
+}
+
+// A ColorScheme represents a family of harmonious colors which share ranges of saturation and lightness
+// but differ in hue.
+type ColorScheme struct {
+	MinSaturation float64 // Minimum saturation in range [0, 1]
+	MaxSaturation float64 // Maximum saturation in range [0, 1]
+	MinLightness  float64 // Minimum lightness in range [0, 1]
+	MaxLightness  float64 // Maximum lightness in range [0, 1]
+}
+
+// Constants for color scheme names
+const (
+	SCHEME_PASTEL = "pastel"
+	SCHEME_VIVID  = "vivid"
+	SCHEME_MUTED  = "muted"
+	SCHEME_DARK   = "dark"
+)
+
+// Mapping of a color scheme name to its [ColorScheme].
+var ColorSchemes = map[string]ColorScheme{
+	SCHEME_PASTEL: {MinSaturation: 0.6, MaxSaturation: 0.8, MinLightness: 0.75, MaxLightness: 0.85},
+	SCHEME_VIVID:  {MinSaturation: 0.7, MaxSaturation: 0.9, MinLightness: 0.45, MaxLightness: 0.55},
+	SCHEME_MUTED:  {MinSaturation: 0.2, MaxSaturation: 0.35, MinLightness: 0.5, MaxLightness: 0.65},
+	SCHEME_DARK:   {MinSaturation: 0.4, MaxSaturation: 0.6, MinLightness: 0.2, MaxLightness: 0.3},
+}
+
+// Color returns a color of the scheme with hue, saturation and lightness drawn from random.
+func (s ColorScheme) Color(random *rand.Rand) Color {
+	hue := random.Float64() * 360
+	saturation := s.MinSaturation + random.Float64()*(s.MaxSaturation-s.MinSaturation)
+	lightness := s.MinLightness + random.Float64()*(s.MaxLightness-s.MinLightness)
+	return HSL(hue, saturation, lightness)
+}
+
+// RandomTextColor returns a color of random hue, drawn from random, which has a contrast ratio of at least
+// minRatio against background.
+//
+// The color is dark for light backgrounds and light for dark backgrounds. If such color does not meet minRatio,
+// it returns [AccessibleTextColor] instead.
+func RandomTextColor(random *rand.Rand, background Color, minRatio float64) (Color, error) {
+	hue := random.Float64() * 360
+	lightness := 0.9
+	if ContrastRatio(background, Black) > ContrastRatio(background, White) {
+		lightness = 0.15
+	}
+	color := HSL(hue, 0.6, lightness)
+	if ContrastRatio(color, background) < minRatio {
+		return AccessibleTextColor(background, minRatio)
+	}
+	return color, nil
+}
+
+// HSL returns hue in degrees [0, 360), saturation and lightness in range [0, 1] of the color.
+func (c Color) HSL() (hue, saturation, lightness float64) {
+	r, g, b := float64(c.R)/0xFF, float64(c.G)/0xFF, float64(c.B)/0xFF
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	lightness = (max + min) / 2
+	chroma := max - min
+	if chroma == 0 {
+		return 0, 0, lightness
+	}
+	saturation = chroma / (1 - math.Abs(2*lightness-1))
+	switch max {
+	case r:
+		hue = math.Mod((g-b)/chroma, 6) * 60
+	case g:
+		hue = ((b-r)/chroma + 2) * 60
+	default:
+		hue = ((r-g)/chroma + 4) * 60
+	}
+	if hue < 0 {
+		hue += 360
+	}
+	return hue, saturation, lightness
+}
+
+// AccessibleTextColor returns a text color which has a contrast ratio of at least minRatio against background.
+//
+// The text color is a shade of the background color i.e. it has the same hue and saturation but
+// is darker for light backgrounds and lighter for dark backgrounds. The shade closest to the background
+// which meets minRatio is returned. If no shade meets minRatio, it returns [ReadableTextColor] and
+// [ErrContrastRatio] when that color does not meet minRatio either.
+//
+// WCAG requires minRatio of 4.5 (AA) or 7.0 (AAA) for normal text.
+func AccessibleTextColor(background Color, minRatio float64) (Color, error) {
+	hue, saturation, lightness := background.HSL()
+	step := 0.01
+	if ContrastRatio(background, White) > ContrastRatio(background, Black) {
+		step = -step
+	}
+	for shade := lightness - step; shade >= 0 && shade <= 1; shade -= step {
+		color := HSL(hue, saturation, shade)
+		if ContrastRatio(color, background) >= minRatio {
+			return color, nil
+		}
+	}
+	color := ReadableTextColor(background)
+	if ContrastRatio(color, background) < minRatio {
+		return color, ErrContrastRatio
+	}
+	return color, nil
+}
@@ -0,0 +1,126 @@
+package img
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestContrastRatio(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Color
+		want float64
+	}{
+		{name: "Black and white", a: Black, b: White, want: 21},
+		{name: "White and black", a: White, b: Black, want: 21},
+		{name: "Same colors", a: Color{0x33, 0x66, 0x99}, b: Color{0x33, 0x66, 0x99}, want: 1},
+		{name: "Default colors", a: Color{0xCC, 0xCC, 0xCC}, b: Color{0x96, 0x96, 0x96}, want: 1.84},
+		{name: "Blue and white", a: Color{0x00, 0x00, 0xFF}, b: White, want: 8.59},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ContrastRatio(tt.a, tt.b); math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("ContrastRatio() = %.2f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadableTextColor(t *testing.T) {
+	tests := []struct {
+		name       string
+		background Color
+		want       Color
+	}{
+		{name: "White background", background: White, want: Black},
+		{name: "Black background", background: Black, want: White},
+		{name: "Light gray background", background: Color{0xCC, 0xCC, 0xCC}, want: Black},
+		{name: "Dark blue background", background: Color{0x33, 0x41, 0x55}, want: White},
+		{name: "Yellow background", background: Color{0xFF, 0xFF, 0x00}, want: Black},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ReadableTextColor(tt.background)
+			if got != tt.want {
+				t.Errorf("ReadableTextColor() = %+v, want %+v", got, tt.want)
+			}
+			if ratio := ContrastRatio(got, tt.background); ratio < 4.5 {
+				t.Errorf("contrast ratio = %.2f, want >= 4.5", ratio)
+			}
+		})
+	}
+}
+
+func TestHSL(t *testing.T) {
+	tests := []struct {
+		name                       string
+		hue, saturation, lightness float64
+		want                       Color
+	}{
+		{name: "Red", hue: 0, saturation: 1, lightness: 0.5, want: Color{0xFF, 0, 0}},
+		{name: "Green", hue: 120, saturation: 1, lightness: 0.5, want: Color{0, 0xFF, 0}},
+		{name: "Blue", hue: 240, saturation: 1, lightness: 0.5, want: Color{0, 0, 0xFF}},
+		{name: "Negative hue", hue: -120, saturation: 1, lightness: 0.5, want: Color{0, 0, 0xFF}},
+		{name: "White", hue: 0, saturation: 0, lightness: 1, want: White},
+		{name: "Gray", hue: 200, saturation: 0, lightness: 0.5, want: Color{0x80, 0x80, 0x80}},
+		{name: "Teal", hue: 180, saturation: 0.5, lightness: 0.4, want: Color{0x33, 0x99, 0x99}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HSL(tt.hue, tt.saturation, tt.lightness); got != tt.want {
+				t.Errorf("HSL() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorScheme(t *testing.T) {
+	for name, scheme := range ColorSchemes {
+		t.Run(name, func(t *testing.T) {
+			first, second := rand.New(rand.NewSource(42)), rand.New(rand.NewSource(42))
+			for i := 0; i < 100; i++ {
+				color := scheme.Color(first)
+				if color != scheme.Color(second) {
+					t.Fatal("expected same colors for same random source")
+				}
+				text, err := RandomTextColor(first, color, 4.5)
+				if err != nil || ContrastRatio(text, color) < 4.5 {
+					t.Fatalf("text color %+v is not readable against %+v", text, color)
+				}
+				second.Float64()
+			}
+		})
+	}
+}
+
+func TestColorHSL(t *testing.T) {
+	colors := []Color{Black, White, {0xFF, 0, 0}, {0x33, 0x99, 0x99}, {0xCC, 0xCC, 0xCC}, {0x63, 0x66, 0xF1}, {0xF5, 0x9E, 0x0B}}
+	for _, color := range colors {
+		if actual := HSL(color.HSL()); actual != color {
+			t.Errorf("HSL(%+v.HSL()) = %+v, want %+v", color, actual, color)
+		}
+	}
+}
+
+func TestAccessibleTextColor(t *testing.T) {
+	backgrounds := []Color{White, Black, {0xCC, 0xCC, 0xCC}, {0x33, 0x41, 0x55}, {0xEF, 0x44, 0x44}, {0xFF, 0xFF, 0x00}, {0x22, 0xC5, 0x5E}}
+	for _, background := range backgrounds {
+		for _, minRatio := range []float64{4.5, 7.0} {
+			color, err := AccessibleTextColor(background, minRatio)
+			if ratio := ContrastRatio(color, background); ratio < minRatio && err == nil {
+				t.Errorf("AccessibleTextColor(%+v, %.1f) = %+v has contrast ratio %.2f", background, minRatio, color, ratio)
+			}
+		}
+	}
+	// No color has a contrast ratio of 7 against middle gray
+	if _, err := AccessibleTextColor(Color{0x80, 0x80, 0x80}, 7.0); err != ErrContrastRatio {
+		t.Errorf("expected error %v, actual error %v", ErrContrastRatio, err)
+	}
+	// The shade keeps the hue of the background
+	shade, _ := AccessibleTextColor(Color{0xEF, 0x44, 0x44}, 4.5)
+	hue, _, _ := shade.HSL()
+	if expected, _, _ := (Color{0xEF, 0x44, 0x44}).HSL(); math.Abs(hue-expected) > 2 {
+		t.Errorf("expected hue = %.0f, actual hue = %.0f", expected, hue)
+	}
+}
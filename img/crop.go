@@ -0,0 +1,171 @@
+package img
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/image/draw"
+)
+
+// Constants for crop strategies
+const (
+	CROP_CENTER    = "center"
+	CROP_ENTROPY   = "entropy"
+	CROP_ATTENTION = "attention"
+)
+
+// Maximum dimension of the thumbnail analysed by entropy and attention crop strategies
+const cropAnalysisSize = 100
+
+// CropRect returns the largest rectangle of src with the aspect ratio of width x height,
+// positioned with given crop strategy.
+//
+//   - CROP_CENTER keeps the centre of src.
+//   - CROP_ENTROPY keeps the area with the most detail, measured as the entropy of the luminance histogram.
+//   - CROP_ATTENTION keeps the area with the most edges, saturated colors and skin tones.
+//
+// If the strategy is unknown, it returns an empty rectangle, error.
+func CropRect(src image.Image, width, height int, strategy string) (image.Rectangle, error) {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	// Fit the target aspect ratio inside the source
+	cropW, cropH := srcW, int(math.Round(float64(srcW)*float64(height)/float64(width)))
+	if cropH > srcH {
+		cropW, cropH = int(math.Round(float64(srcH)*float64(width)/float64(height))), srcH
+	}
+	cropW, cropH = int(math.Max(1, float64(cropW))), int(math.Max(1, float64(cropH)))
+
+	var offset float64 // Offset along the free axis as a fraction of the free space
+	switch strategy {
+	case CROP_CENTER:
+		offset = 0.5
+	case CROP_ENTROPY:
+		offset = bestCropOffset(src, cropW, cropH, windowEntropy)
+	case CROP_ATTENTION:
+		offset = bestCropOffset(src, cropW, cropH, windowAttention)
+	default:
+		return image.Rectangle{}, fmt.Errorf("unknown crop strategy %s", strategy)
+	}
+	x := bounds.Min.X + int(math.Round(float64(srcW-cropW)*offset))
+	y := bounds.Min.Y + int(math.Round(float64(srcH-cropH)*offset))
+	return image.Rect(x, y, x+cropW, y+cropH), nil
+}
+
+// A windowScoreFunc returns the score of the window of the thumbnail, higher score for the more interesting window.
+type windowScoreFunc func(thumbnail *image.NRGBA, window image.Rectangle) float64
+
+// bestCropOffset returns the offset of the cropW x cropH window of src along its free axis, as a fraction of the
+// free space, for which score is the highest. The windows are compared on a thumbnail of src.
+func bestCropOffset(src image.Image, cropW, cropH int, score windowScoreFunc) float64 {
+	bounds := src.Bounds()
+	free := math.Max(float64(bounds.Dx()-cropW), float64(bounds.Dy()-cropH))
+	if free <= 0 {
+		return 0.5
+	}
+	scale := math.Min(1, cropAnalysisSize/math.Max(float64(bounds.Dx()), float64(bounds.Dy())))
+	thumbW, thumbH := int(math.Max(1, math.Round(float64(bounds.Dx())*scale))), int(math.Max(1, math.Round(float64(bounds.Dy())*scale)))
+	thumbnail := image.NewNRGBA(image.Rect(0, 0, thumbW, thumbH))
+	draw.ApproxBiLinear.Scale(thumbnail, thumbnail.Bounds(), src, bounds, draw.Src, nil)
+
+	windowW, windowH := int(math.Max(1, math.Round(float64(cropW)*scale))), int(math.Max(1, math.Round(float64(cropH)*scale)))
+	windowW, windowH = int(math.Min(float64(windowW), float64(thumbW))), int(math.Min(float64(windowH), float64(thumbH)))
+	steps := int(math.Max(float64(thumbW-windowW), float64(thumbH-windowH)))
+	if steps == 0 {
+		return 0.5
+	}
+
+	bestStep, bestScore := steps/2, math.Inf(-1)
+	for step := 0; step <= steps; step++ {
+		window := image.Rect(0, 0, windowW, windowH)
+		if thumbW-windowW > thumbH-windowH {
+			window = window.Add(image.Pt(step, 0))
+		} else {
+			window = window.Add(image.Pt(0, step))
+		}
+		// Prefer the window closer to the centre on equal scores
+		if s := score(thumbnail, window); s > bestScore || (s == bestScore && math.Abs(float64(step)-float64(steps)/2) < math.Abs(float64(bestStep)-float64(steps)/2)) {
+			bestStep, bestScore = step, s
+		}
+	}
+	return float64(bestStep) / float64(steps)
+}
+
+// windowEntropy returns the Shannon entropy of the luminance histogram of the window.
+func windowEntropy(thumbnail *image.NRGBA, window image.Rectangle) float64 {
+	var histogram [256]int
+	for y := window.Min.Y; y < window.Max.Y; y++ {
+		for x := window.Min.X; x < window.Max.X; x++ {
+			histogram[pixelLuma(thumbnail.NRGBAAt(x, y))]++
+		}
+	}
+	total := float64(window.Dx() * window.Dy())
+	entropy := 0.0
+	for _, count := range histogram {
+		if count > 0 {
+			p := float64(count) / total
+			entropy -= p * math.Log2(p)
+		}
+	}
+	return entropy
+}
+
+// windowAttention returns the sum of the edge strength, color saturation and skin tone of the pixels in the window.
+func windowAttention(thumbnail *image.NRGBA, window image.Rectangle) float64 {
+	bounds := thumbnail.Bounds()
+	score := 0.0
+	for y := window.Min.Y; y < window.Max.Y; y++ {
+		for x := window.Min.X; x < window.Max.X; x++ {
+			pixel := thumbnail.NRGBAAt(x, y)
+			luma := float64(pixelLuma(pixel))
+			if x+1 < bounds.Max.X {
+				score += math.Abs(luma-float64(pixelLuma(thumbnail.NRGBAAt(x+1, y)))) / 0xFF
+			}
+			if y+1 < bounds.Max.Y {
+				score += math.Abs(luma-float64(pixelLuma(thumbnail.NRGBAAt(x, y+1)))) / 0xFF
+			}
+			r, g, b := float64(pixel.R), float64(pixel.G), float64(pixel.B)
+			if max := math.Max(r, math.Max(g, b)); max > 0 {
+				score += (max - math.Min(r, math.Min(g, b))) / max
+			}
+			if isSkinTone(pixel) {
+				score += 2
+			}
+		}
+	}
+	return score
+}
+
+// pixelLuma returns the Rec. 601 luma of the pixel.
+func pixelLuma(pixel color.NRGBA) uint8 {
+	return uint8((299*int(pixel.R) + 587*int(pixel.G) + 114*int(pixel.B)) / 1000)
+}
+
+// isSkinTone returns true if the pixel color is in the RGB range commonly classified as human skin.
+func isSkinTone(pixel color.NRGBA) bool {
+	r, g, b := int(pixel.R), int(pixel.G), int(pixel.B)
+	min := g
+	if b < min {
+		min = b
+	}
+	return r > 95 && g > 40 && b > 20 && r > g && r > b && r-min > 15 && r-g > 15
+}
+
+// AverageColor returns the average color of the image, sampled on a grid of at most 100x100 pixels.
+func AverageColor(src image.Image) Color {
+	bounds := src.Bounds()
+	step := int(math.Max(1, math.Ceil(math.Max(float64(bounds.Dx()), float64(bounds.Dy()))/cropAnalysisSize)))
+	var r, g, b, count uint64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			pixel := color.NRGBAModel.Convert(src.At(x, y)).(color.NRGBA)
+			r, g, b, count = r+uint64(pixel.R), g+uint64(pixel.G), b+uint64(pixel.B), count+1
+		}
+	}
+	if count == 0 {
+		return Black
+	}
+	return Color{R: uint8(r / count), G: uint8(g / count), B: uint8(b / count)}
+}
@@ -0,0 +1,57 @@
+package img
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math/rand"
+	"testing"
+)
+
+func TestCropRect(t *testing.T) {
+	// Flat gray left half and detailed, colorful right half
+	src := image.NewNRGBA(image.Rect(0, 0, 400, 100))
+	draw.Draw(src, src.Bounds(), image.NewUniform(color.NRGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xFF}), image.Point{}, draw.Src)
+	random := rand.New(rand.NewSource(1))
+	for y := 0; y < 100; y++ {
+		for x := 300; x < 400; x++ {
+			src.SetNRGBA(x, y, color.NRGBA{R: uint8(random.Intn(256)), G: uint8(random.Intn(256)), B: uint8(random.Intn(256)), A: 0xFF})
+		}
+	}
+
+	tests := []struct {
+		strategy      string
+		width, height int
+		want          image.Rectangle
+	}{
+		{strategy: CROP_CENTER, width: 100, height: 100, want: image.Rect(150, 0, 250, 100)},
+		{strategy: CROP_CENTER, width: 400, height: 50, want: image.Rect(0, 25, 400, 75)},
+		{strategy: CROP_ENTROPY, width: 50, height: 50, want: image.Rect(300, 0, 400, 100)},
+		{strategy: CROP_ATTENTION, width: 50, height: 50, want: image.Rect(300, 0, 400, 100)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			got, err := CropRect(src, tt.width, tt.height, tt.strategy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("CropRect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := CropRect(src, 10, 10, "smart"); err == nil {
+		t.Error("expected error for unknown crop strategy")
+	}
+}
+
+func TestAverageColor(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 300, 200))
+	draw.Draw(src, image.Rect(0, 0, 150, 200), image.NewUniform(color.NRGBA{R: 0xFF, A: 0xFF}), image.Point{}, draw.Src)
+	draw.Draw(src, image.Rect(150, 0, 300, 200), image.NewUniform(color.NRGBA{B: 0xFF, A: 0xFF}), image.Point{}, draw.Src)
+
+	if got, want := AverageColor(src), (Color{R: 0x7F, B: 0x7F}); got != want {
+		t.Errorf("AverageColor() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,215 @@
+package img
+
+import (
+	"image"
+	"math"
+
+	"github.com/fogleman/gg"
+)
+
+// Constants for text box kinds
+const (
+	TEXT_BOX_NONE = ""
+	TEXT_BOX_RECT = "box"
+	TEXT_BOX_PILL = "pill"
+)
+
+// Alpha from which a pixel of the text is inside a glyph when drawing its outline
+const outlineThreshold = 0x80
+
+// A TextEffects stores parameters for the outline, shadow and background box of the text.
+// All lengths are in pixels before scaling.
+type TextEffects struct {
+	StrokeWidth  float64 // Width of the outline around the glyphs, 0 for no outline
+	StrokeColor  *Color  // Color to use for outline
+	ShadowColor  *Color  // Color to use for shadow, nil for no shadow
+	ShadowBlur   float64 // Blur radius of the shadow
+	ShadowOffset float64 // Offset of the shadow towards the bottom right
+	Box          string  // Box kind, one of TEXT_BOX_NONE, TEXT_BOX_RECT or TEXT_BOX_PILL
+	BoxColor     *Color  // Color to use for box
+	BoxPadding   float64 // Space between the text and the box edges, twice as much at the ends of a pill
+}
+
+// scaled returns a copy of the effects with all lengths multiplied by scale.
+func (e *TextEffects) scaled(scale float64) *TextEffects {
+	scaledEffects := *e
+	scaledEffects.StrokeWidth *= scale
+	scaledEffects.ShadowBlur *= scale
+	scaledEffects.ShadowOffset *= scale
+	scaledEffects.BoxPadding *= scale
+	return &scaledEffects
+}
+
+// DrawTextEffects draws the text drawn by drawText on the canvas with the effects.
+//
+// The text is drawn on a transparent layer of the canvas size, so the effects apply to any text layout.
+// They are painted from back to front: the box around all the text, the shadow, the outline and the text itself.
+// The outline is drawn outside the glyphs and the shadow falls from both the outline and the glyphs.
+// If drawText fails, it returns that error.
+func DrawTextEffects(canvas *gg.Context, effects *TextEffects, drawText func(layer *gg.Context) error) error {
+	text := gg.NewContext(canvas.Width(), canvas.Height())
+	if err := drawText(text); err != nil {
+		return err
+	}
+	textImage := text.Image().(*image.RGBA)
+
+	// Alpha of the glyphs and their outline, from which the box and the shadow are made
+	shape := alphaOf(textImage)
+	if effects.StrokeWidth > 0 {
+		shape = outlineAlpha(shape, effects.StrokeWidth)
+	}
+
+	if effects.Box != TEXT_BOX_NONE {
+		if ink := opaqueBounds(shape); !ink.Empty() {
+			x := float64(ink.Min.X) - effects.BoxPadding
+			y := float64(ink.Min.Y) - effects.BoxPadding
+			w := float64(ink.Dx()) + 2*effects.BoxPadding
+			h := float64(ink.Dy()) + 2*effects.BoxPadding
+			radius := 0.0
+			if effects.Box == TEXT_BOX_PILL {
+				// Round ends of the pill take room at the sides of the text
+				x -= effects.BoxPadding
+				w += 2 * effects.BoxPadding
+				radius = math.Min(w, h) / 2
+			}
+			canvas.DrawRoundedRectangle(x, y, w, h, radius)
+			canvas.SetRGBA255(int(effects.BoxColor.R), int(effects.BoxColor.G), int(effects.BoxColor.B), 0xFF)
+			canvas.Fill()
+		}
+	}
+	if effects.ShadowColor != nil {
+		shadow := colorize(shape, effects.ShadowColor, shadowOpacity)
+		offset := int(math.Round(effects.ShadowOffset))
+		canvas.DrawImage(blurImage(shadow, effects.ShadowBlur), offset, offset)
+	}
+	if effects.StrokeWidth > 0 {
+		canvas.DrawImage(colorize(shape, effects.StrokeColor, 1), 0, 0)
+	}
+	canvas.DrawImage(textImage, 0, 0)
+	return nil
+}
+
+// alphaOf returns the alpha channel of the image.
+func alphaOf(src *image.RGBA) *image.Alpha {
+	alpha := image.NewAlpha(src.Bounds())
+	for i := range alpha.Pix {
+		alpha.Pix[i] = src.Pix[4*i+3]
+	}
+	return alpha
+}
+
+// outlineAlpha returns the alpha of the glyphs in src together with an outline of given width around them.
+//
+// The outline is round and anti-aliased. It is made from the distance of each pixel to the nearest pixel
+// inside a glyph, computed with an exact euclidean distance transform.
+func outlineAlpha(src *image.Alpha, width float64) *image.Alpha {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	distances := make([]float64, w*h)
+	for i, a := range src.Pix {
+		if a < outlineThreshold {
+			distances[i] = math.Inf(1)
+		}
+	}
+	// Squared distances along the columns and then along the rows
+	size := w
+	if h > size {
+		size = h
+	}
+	line, transformed := make([]float64, size), make([]float64, size)
+	parabolas, boundaries := make([]int, size), make([]float64, size+1)
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			line[y] = distances[y*w+x]
+		}
+		distanceTransform(line[:h], transformed, parabolas, boundaries)
+		for y := 0; y < h; y++ {
+			distances[y*w+x] = transformed[y]
+		}
+	}
+	for y := 0; y < h; y++ {
+		distanceTransform(distances[y*w:(y+1)*w], transformed, parabolas, boundaries)
+		copy(distances[y*w:(y+1)*w], transformed[:w])
+	}
+
+	outline := image.NewAlpha(bounds)
+	for i, distance := range distances {
+		// Edge of a glyph lies about half a pixel outside the centre of its nearest inside pixel
+		coverage := math.Max(0, math.Min(1, width+1-math.Sqrt(distance)))
+		outline.Pix[i] = uint8(math.Max(float64(src.Pix[i]), math.Round(coverage*0xFF)))
+	}
+	return outline
+}
+
+// distanceTransform writes the squared euclidean distance transform of the sampled function f in result,
+// as the lower envelope of parabolas rooted at each sample (Felzenszwalb and Huttenlocher).
+//
+// Samples of f are 0 inside and +Inf outside for a distance to the nearest inside sample. The slices parabolas and
+// boundaries are working memory of at least len(f) and len(f)+1 elements.
+func distanceTransform(f, result []float64, parabolas []int, boundaries []float64) {
+	n := len(f)
+	k := -1
+	for q := 0; q < n; q++ {
+		if math.IsInf(f[q], 1) {
+			continue
+		}
+		for k >= 0 {
+			p := parabolas[k]
+			s := ((f[q] + float64(q*q)) - (f[p] + float64(p*p))) / float64(2*q-2*p)
+			if s > boundaries[k] {
+				k++
+				parabolas[k], boundaries[k] = q, s
+				break
+			}
+			k--
+		}
+		if k < 0 {
+			k = 0
+			parabolas[0], boundaries[0] = q, math.Inf(-1)
+		}
+		boundaries[k+1] = math.Inf(1)
+	}
+	if k < 0 {
+		// No inside sample on the line
+		for q := 0; q < n; q++ {
+			result[q] = math.Inf(1)
+		}
+		return
+	}
+	k = 0
+	for q := 0; q < n; q++ {
+		for boundaries[k+1] < float64(q) {
+			k++
+		}
+		p := parabolas[k]
+		result[q] = float64((q-p)*(q-p)) + f[p]
+	}
+}
+
+// opaqueBounds returns the smallest rectangle which contains all the pixels of the alpha image which are not transparent.
+func opaqueBounds(alpha *image.Alpha) image.Rectangle {
+	bounds := alpha.Bounds()
+	ink := image.Rectangle{}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if alpha.AlphaAt(x, y).A > 0 {
+				ink = ink.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return ink
+}
+
+// colorize returns an image of given color with the alpha of the alpha image multiplied by opacity.
+func colorize(alpha *image.Alpha, color *Color, opacity float64) *image.RGBA {
+	colored := image.NewRGBA(alpha.Bounds())
+	for i, a := range alpha.Pix {
+		// Colors are premultiplied by alpha
+		scaled := float64(a) * opacity / 0xFF
+		colored.Pix[4*i] = uint8(math.Round(float64(color.R) * scaled))
+		colored.Pix[4*i+1] = uint8(math.Round(float64(color.G) * scaled))
+		colored.Pix[4*i+2] = uint8(math.Round(float64(color.B) * scaled))
+		colored.Pix[4*i+3] = uint8(math.Round(0xFF * scaled))
+	}
+	return colored
+}
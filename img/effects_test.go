@@ -0,0 +1,91 @@
+package img
+
+import (
+	"image"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/fogleman/gg"
+)
+
+func TestDistanceTransform(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	n := 40
+	f := make([]float64, n)
+	for i := range f {
+		f[i] = math.Inf(1)
+		if random.Intn(6) == 0 {
+			f[i] = 0
+		}
+	}
+	result := make([]float64, n)
+	distanceTransform(f, result, make([]int, n), make([]float64, n+1))
+	for q := 0; q < n; q++ {
+		want := math.Inf(1)
+		for p := 0; p < n; p++ {
+			if f[p] == 0 {
+				want = math.Min(want, float64((q-p)*(q-p)))
+			}
+		}
+		if result[q] != want {
+			t.Errorf("distance at %d = %f, want %f", q, result[q], want)
+		}
+	}
+}
+
+func TestOutlineAlpha(t *testing.T) {
+	src := image.NewAlpha(image.Rect(0, 0, 21, 21))
+	src.Pix[src.PixOffset(10, 10)] = 0xFF
+	outline := outlineAlpha(src, 4)
+	tests := []struct {
+		x, y int
+		want uint8
+	}{
+		{x: 10, y: 10, want: 0xFF},
+		{x: 14, y: 10, want: 0xFF},
+		{x: 13, y: 13, want: 0xc1}, // Distance 4.24 is partially covered
+		{x: 16, y: 10, want: 0},
+		{x: 0, y: 0, want: 0},
+	}
+	for _, tt := range tests {
+		if got := outline.AlphaAt(tt.x, tt.y).A; got != tt.want {
+			t.Errorf("outline alpha at (%d, %d) = %#x, want %#x", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestDrawTextEffects(t *testing.T) {
+	drawSquare := func(layer *gg.Context) error {
+		layer.DrawRectangle(40, 40, 20, 20)
+		layer.SetRGB(1, 1, 1)
+		layer.Fill()
+		return nil
+	}
+	tests := []struct {
+		name    string
+		effects *TextEffects
+		ink     image.Rectangle
+	}{
+		{name: "Stroke", effects: &TextEffects{StrokeWidth: 5, StrokeColor: &Black}, ink: image.Rect(35, 35, 65, 65)},
+		{name: "Box", effects: &TextEffects{Box: TEXT_BOX_RECT, BoxColor: &Black, BoxPadding: 10}, ink: image.Rect(30, 30, 70, 70)},
+		{name: "Pill", effects: &TextEffects{Box: TEXT_BOX_PILL, BoxColor: &Black, BoxPadding: 10}, ink: image.Rect(20, 30, 80, 70)},
+		{name: "Shadow", effects: &TextEffects{ShadowColor: &Black, ShadowOffset: 10}, ink: image.Rect(50, 50, 70, 70)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canvas := gg.NewContext(100, 100)
+			FillBackground(canvas, &White)
+			if err := DrawTextEffects(canvas, tt.effects, drawSquare); err != nil {
+				t.Fatal(err)
+			}
+			if got := inkBounds(canvas); got != tt.ink {
+				t.Errorf("ink bounds = %v, want %v", got, tt.ink)
+			}
+			// Text is drawn over the effects
+			if r, g, b, _ := canvas.Image().At(50, 50).RGBA(); r>>8 != 0xFF || g>>8 != 0xFF || b>>8 != 0xFF {
+				t.Errorf("expected white text at the centre")
+			}
+		})
+	}
+}
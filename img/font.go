@@ -0,0 +1,134 @@
+package img
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	otfont "github.com/go-text/typesetting/font"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomedium"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// Name of the font used to draw text when no font is given
+const DefaultFont = "Go-Regular"
+
+// Extensions of the font files loaded by [LoadFonts]
+var fontExtensions = []string{".ttf", ".otf"}
+
+// Mapping of a font name to its parsed font.
+var fonts = map[string]*otfont.Font{}
+
+// Mapping of a parsed font to the same font parsed by [truetype], for the fonts with TrueType outlines.
+//
+// Glyphs of these fonts are drawn like the text of gg, see [glyphFace].
+var truetypeFonts = map[*otfont.Font]*truetype.Font{}
+
+// Fonts in which glyphs missing from the font of the text are looked up in order, see [SetFallbackFonts].
+var fallbackFonts = []*otfont.Font{}
+
+func init() {
+	// Register the built-in Go fonts
+	builtinFonts := map[string][]byte{
+		DefaultFont:     goregular.TTF,
+		"Go-Bold":       gobold.TTF,
+		"Go-Italic":     goitalic.TTF,
+		"Go-BoldItalic": gobolditalic.TTF,
+		"Go-Medium":     gomedium.TTF,
+		"Go-Mono":       gomono.TTF,
+		"Go-MonoBold":   gomonobold.TTF,
+	}
+	for name, ttf := range builtinFonts {
+		if err := registerFont(name, ttf); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// LoadFonts registers all the TrueType (.ttf) and OpenType (.otf) font files present in directory dir.
+//
+// Each font is registered with the file name without extension e.g. Inter-Bold.ttf is registered as Inter-Bold.
+// If an error occurs while reading or parsing a font file, it returns that error.
+func LoadFonts(dir string) error {
+	for _, extension := range fontExtensions {
+		paths, err := filepath.Glob(filepath.Join(dir, "*"+extension))
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if err := registerFont(strings.TrimSuffix(filepath.Base(path), extension), data); err != nil {
+				return fmt.Errorf("failed to parse font %s: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// registerFont parses the font data and registers it with given name.
+//
+// If an error occurs while parsing the font, it returns that error.
+func registerFont(name string, data []byte) error {
+	face, err := otfont.ParseTTF(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	fonts[name] = face.Font
+	// Fonts with CFF outlines are not parsed by truetype, their glyphs are drawn by their outlines
+	if font, err := truetype.Parse(data); err == nil {
+		truetypeFonts[face.Font] = font
+	}
+	return nil
+}
+
+// SetFallbackFonts sets the chain of registered fonts in which glyphs missing from the font of the text are looked up.
+//
+// A character is drawn with the first font of the chain which has a glyph for it, e.g. a CJK font followed by an emoji font.
+// If no font has a glyph for it, the missing glyph of the font of the text is drawn.
+// If a font is not registered with one of the names, it returns error.
+func SetFallbackFonts(names []string) error {
+	chain := make([]*otfont.Font, 0, len(names))
+	for _, name := range names {
+		font, err := getFont(strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+		chain = append(chain, font)
+	}
+	fallbackFonts = chain
+	return nil
+}
+
+// HasFont returns true if a font is registered with given name otherwise it returns false.
+//
+// An empty name refers to [DefaultFont].
+func HasFont(name string) bool {
+	_, err := getFont(name)
+	return err == nil
+}
+
+// getFont returns the font registered with given name.
+//
+// If name is empty, it returns [DefaultFont].
+// If no font is registered with name, it returns nil, error.
+func getFont(name string) (*otfont.Font, error) {
+	if name == "" {
+		name = DefaultFont
+	}
+	font, exists := fonts[name]
+	if !exists {
+		return nil, fmt.Errorf("font not found with name %s", name)
+	}
+	return font, nil
+}
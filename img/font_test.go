@@ -0,0 +1,77 @@
+package img
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/image/font/gofont/gosmallcaps"
+	"golang.org/x/image/font/gofont/gosmallcapsitalic"
+)
+
+func TestHasFont(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "", want: true},
+		{name: DefaultFont, want: true},
+		{name: "Go-Bold", want: true},
+		{name: "Comic-Sans", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasFont(tt.name); got != tt.want {
+				t.Errorf("HasFont(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFonts(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Go-SmallCaps.ttf"), gosmallcaps.TTF, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.txt"), []byte("not a font"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadFonts(dir); err != nil {
+		t.Fatal(err)
+	}
+	if !HasFont("Go-SmallCaps") {
+		t.Error("expected font Go-SmallCaps to be loaded")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Go-SmallCapsItalic.otf"), gosmallcapsitalic.TTF, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadFonts(dir); err != nil {
+		t.Fatal(err)
+	}
+	if !HasFont("Go-SmallCapsItalic") {
+		t.Error("expected font Go-SmallCapsItalic to be loaded")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "Broken.ttf"), []byte("not a font"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadFonts(dir); err == nil {
+		t.Error("expected error for invalid font file")
+	}
+}
+
+func TestSetFallbackFonts(t *testing.T) {
+	defer SetFallbackFonts(nil)
+	if err := SetFallbackFonts([]string{"Go-Bold", " Go-Mono"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(fallbackFonts) != 2 || fallbackFonts[0] != fonts["Go-Bold"] || fallbackFonts[1] != fonts["Go-Mono"] {
+		t.Errorf("unexpected fallback fonts = %v", fallbackFonts)
+	}
+	if err := SetFallbackFonts([]string{"Go-Bold", "Comic-Sans"}); err == nil {
+		t.Error("expected error for unknown fallback font")
+	}
+	if len(fallbackFonts) != 2 {
+		t.Error("expected fallback fonts to be unchanged after error")
+	}
+}
@@ -0,0 +1,215 @@
+package img
+
+import (
+	"errors"
+	"image"
+	"math"
+
+	"github.com/cod3rboy/yaps/utils"
+	"github.com/fogleman/gg"
+)
+
+// Constants for border styles
+const (
+	BORDER_SOLID  = "solid"
+	BORDER_DASHED = "dashed"
+)
+
+// Constants for shadow kinds
+const (
+	SHADOW_NONE  = ""
+	SHADOW_OUTER = "outer"
+	SHADOW_INSET = "inset"
+)
+
+// Opacity of the shadow color
+const shadowOpacity = 0.5
+
+// Length of dashes and gaps of a dashed border as multiples of the border width
+const (
+	borderDashLength = 3
+	borderGapLength  = 2
+)
+
+// A FrameParams stores parameters for the border, corners and shadow of an image.
+// All lengths are in pixels before scaling.
+type FrameParams struct {
+	BorderWidth  float64 // Width of the border, 0 for no border
+	BorderColor  *Color  // Color to use for border
+	BorderStyle  string  // Border style, either BORDER_SOLID or BORDER_DASHED
+	Radius       float64 // Corner radius, 0 for square corners
+	Shadow       string  // Shadow kind, one of SHADOW_NONE, SHADOW_OUTER or SHADOW_INSET
+	ShadowColor  *Color  // Color to use for shadow
+	ShadowBlur   float64 // Blur radius of the shadow
+	ShadowOffset float64 // Offset of the shadow towards the bottom right
+}
+
+// scaled returns a copy of the frame with all lengths multiplied by scale.
+func (f *FrameParams) scaled(scale float64) *FrameParams {
+	scaledFrame := *f
+	scaledFrame.BorderWidth *= scale
+	scaledFrame.Radius *= scale
+	scaledFrame.ShadowBlur *= scale
+	scaledFrame.ShadowOffset *= scale
+	return &scaledFrame
+}
+
+// Errors returned by [FrameParams.Validate], each naming the frame parameter which is out of range
+var (
+	ErrFrameBorderWidth  = errors.New("frame border width must be a finite non-negative length")
+	ErrFrameBorderStyle  = errors.New("unknown frame border style")
+	ErrFrameRadius       = errors.New("frame radius must be a finite non-negative length")
+	ErrFrameShadow       = errors.New("unknown frame shadow")
+	ErrFrameShadowBlur   = errors.New("frame shadow blur must be a finite non-negative length which fits in the image")
+	ErrFrameShadowOffset = errors.New("frame shadow offset must be a finite non-negative length which fits in the image")
+)
+
+// Validate returns an error if a frame parameter is out of range or the outer shadow does not fit in an image
+// of given dimensions. The error is one of the ErrFrame errors, naming the parameter at fault.
+//
+// The frame lengths and the dimensions are before scaling, and they are checked after scaling by scale
+// like they are drawn by [Generate].
+func (f *FrameParams) Validate(width, height int, scale float64) error {
+	return f.scaled(scale).validate(float64(utils.ScaleDimension(width, scale)), float64(utils.ScaleDimension(height, scale)))
+}
+
+// validate returns an error if a frame parameter is out of range or the outer shadow does not fit in an image
+// of given dimensions in pixels, see [FrameParams.Validate].
+func (f *FrameParams) validate(width, height float64) error {
+	if !isLength(f.BorderWidth) {
+		return ErrFrameBorderWidth
+	}
+	if f.BorderWidth > 0 && f.BorderStyle != BORDER_SOLID && f.BorderStyle != BORDER_DASHED {
+		return ErrFrameBorderStyle
+	}
+	if !isLength(f.Radius) {
+		return ErrFrameRadius
+	}
+	if f.Shadow != SHADOW_NONE && f.Shadow != SHADOW_OUTER && f.Shadow != SHADOW_INSET {
+		return ErrFrameShadow
+	}
+	side := math.Min(width, height)
+	if !isLength(f.ShadowBlur) || (f.Shadow == SHADOW_OUTER && 2*f.ShadowBlur+1 > side) {
+		return ErrFrameShadowBlur
+	}
+	if !isLength(f.ShadowOffset) || (f.Shadow == SHADOW_OUTER && 2*f.ShadowBlur+f.ShadowOffset+1 > side) {
+		return ErrFrameShadowOffset
+	}
+	return nil
+}
+
+// isLength returns true if value is a finite non-negative length otherwise it returns false.
+func isLength(value float64) bool {
+	return value >= 0 && !math.IsInf(value, 1)
+}
+
+// DrawFrame draws a card with the frame on the canvas, with the card content drawn by drawContent.
+//
+// The card fills the canvas, except for the space taken by an outer shadow. The area outside the rounded corners
+// and around the card is filled with the matte color, or left transparent if matte is nil.
+// The frame must fit in the canvas, see [FrameParams.Validate]. If drawContent fails, it returns that error.
+func DrawFrame(canvas *gg.Context, frame *FrameParams, matte *Color, drawContent func(content *gg.Context) error) error {
+	w, h := float64(canvas.Width()), float64(canvas.Height())
+	if matte != nil {
+		FillBackground(canvas, matte)
+	}
+
+	// Card leaves space for the outer shadow
+	x, y, cardW, cardH := 0.0, 0.0, w, h
+	if frame.Shadow == SHADOW_OUTER {
+		x, y = frame.ShadowBlur, frame.ShadowBlur
+		cardW, cardH = w-2*frame.ShadowBlur-frame.ShadowOffset, h-2*frame.ShadowBlur-frame.ShadowOffset
+	}
+	radius := math.Min(frame.Radius, math.Min(cardW, cardH)/2)
+
+	if frame.Shadow == SHADOW_OUTER {
+		shadow := gg.NewContext(canvas.Width(), canvas.Height())
+		setShadowColor(shadow, frame.ShadowColor)
+		shadow.DrawRoundedRectangle(x+frame.ShadowOffset, y+frame.ShadowOffset, cardW, cardH, radius)
+		shadow.Fill()
+		canvas.DrawImage(blurImage(shadow.Image().(*image.RGBA), frame.ShadowBlur), 0, 0)
+	}
+
+	content := gg.NewContext(int(math.Round(cardW)), int(math.Round(cardH)))
+	if err := drawContent(content); err != nil {
+		return err
+	}
+	if frame.Shadow == SHADOW_INSET {
+		// Shadow of the area around the card falls inside the card edges
+		shadow := gg.NewContext(content.Width(), content.Height())
+		setShadowColor(shadow, frame.ShadowColor)
+		spread := frame.ShadowBlur + frame.ShadowOffset
+		shadow.DrawRectangle(-spread, -spread, cardW+2*spread, cardH+2*spread)
+		shadow.DrawRoundedRectangle(frame.ShadowOffset, frame.ShadowOffset, cardW, cardH, radius)
+		shadow.SetFillRule(gg.FillRuleEvenOdd)
+		shadow.Fill()
+		content.DrawImage(blurImage(shadow.Image().(*image.RGBA), frame.ShadowBlur), 0, 0)
+	}
+
+	canvas.DrawRoundedRectangle(x, y, cardW, cardH, radius)
+	canvas.Clip()
+	canvas.DrawImage(content.Image(), int(math.Round(x)), int(math.Round(y)))
+	canvas.ResetClip()
+
+	if frame.BorderWidth > 0 {
+		// Stroke is centered on the path, so the path is inset by half the border width
+		inset := frame.BorderWidth / 2
+		canvas.DrawRoundedRectangle(x+inset, y+inset, cardW-frame.BorderWidth, cardH-frame.BorderWidth, math.Max(0, radius-inset))
+		canvas.SetRGBA255(int(frame.BorderColor.R), int(frame.BorderColor.G), int(frame.BorderColor.B), 0xFF)
+		canvas.SetLineWidth(frame.BorderWidth)
+		if frame.BorderStyle == BORDER_DASHED {
+			canvas.SetDash(frame.BorderWidth*borderDashLength, frame.BorderWidth*borderGapLength)
+		}
+		canvas.Stroke()
+		canvas.SetDash()
+	}
+	return nil
+}
+
+// setShadowColor sets the canvas color to the shadow color with shadowOpacity.
+func setShadowColor(canvas *gg.Context, color *Color) {
+	canvas.SetRGBA(float64(color.R)/0xFF, float64(color.G)/0xFF, float64(color.B)/0xFF, shadowOpacity)
+}
+
+// blurImage blurs the image in place with three passes of a box blur, which approximates a gaussian blur
+// spreading over radius pixels, and returns the image.
+func blurImage(src *image.RGBA, radius float64) *image.RGBA {
+	box := int(math.Round(radius / 3))
+	if box < 1 {
+		return src
+	}
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	buffer := make([]uint8, len(src.Pix))
+	for pass := 0; pass < 3; pass++ {
+		boxBlur(src.Pix, buffer, w, h, src.Stride, 4, box)
+		boxBlur(buffer, src.Pix, h, w, 4, src.Stride, box)
+	}
+	return src
+}
+
+// boxBlur averages each pixel of src with its box neighbours along one axis and writes the result in dst.
+//
+// The axis is given by its length and the byte step between pixels along it, lines by their count and byte step.
+func boxBlur(src, dst []uint8, length, lines, lineStep, pixelStep, box int) {
+	size := 2*box + 1
+	for line := 0; line < lines; line++ {
+		start := line * lineStep
+		for c := 0; c < 4; c++ {
+			// Running sum of the window, pixels outside the image are transparent
+			sum := 0
+			for i := 0; i <= box && i < length; i++ {
+				sum += int(src[start+i*pixelStep+c])
+			}
+			for i := 0; i < length; i++ {
+				dst[start+i*pixelStep+c] = uint8(sum / size)
+				if next := i + box + 1; next < length {
+					sum += int(src[start+next*pixelStep+c])
+				}
+				if previous := i - box; previous >= 0 {
+					sum -= int(src[start+previous*pixelStep+c])
+				}
+			}
+		}
+	}
+}
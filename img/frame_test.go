@@ -0,0 +1,128 @@
+package img
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/fogleman/gg"
+)
+
+func TestDrawFrame(t *testing.T) {
+	background, border := Color{R: 0x33, G: 0x66, B: 0x99}, Color{R: 0xFF}
+	drawContent := func(content *gg.Context) error {
+		FillBackground(content, &background)
+		return nil
+	}
+	colorAt := func(canvas *gg.Context, x, y int) color.NRGBA {
+		return color.NRGBAModel.Convert(canvas.Image().At(x, y)).(color.NRGBA)
+	}
+	opaque := func(c Color) color.NRGBA {
+		return color.NRGBA{R: c.R, G: c.G, B: c.B, A: 0xFF}
+	}
+
+	tests := []struct {
+		name   string
+		matte  *Color
+		frame  FrameParams
+		points map[[2]int]color.NRGBA
+	}{
+		{
+			name:   "Rounded corners",
+			frame:  FrameParams{Radius: 20},
+			points: map[[2]int]color.NRGBA{{0, 0}: {}, {50, 50}: opaque(background), {50, 0}: opaque(background)},
+		},
+		{
+			name:   "Rounded corners with matte",
+			matte:  &White,
+			frame:  FrameParams{Radius: 20},
+			points: map[[2]int]color.NRGBA{{0, 0}: opaque(White), {50, 50}: opaque(background)},
+		},
+		{
+			name:   "Outer shadow with matte",
+			matte:  &border,
+			frame:  FrameParams{Shadow: SHADOW_OUTER, ShadowColor: &Black, ShadowBlur: 9, ShadowOffset: 3},
+			points: map[[2]int]color.NRGBA{{0, 0}: opaque(border), {50, 50}: opaque(background)},
+		},
+		{
+			name:   "Solid border",
+			frame:  FrameParams{BorderWidth: 4, BorderColor: &border, BorderStyle: BORDER_SOLID},
+			points: map[[2]int]color.NRGBA{{1, 50}: opaque(border), {50, 2}: opaque(border), {6, 50}: opaque(background)},
+		},
+		{
+			name:   "Dashed border",
+			frame:  FrameParams{BorderWidth: 4, BorderColor: &border, BorderStyle: BORDER_DASHED},
+			points: map[[2]int]color.NRGBA{{6, 2}: opaque(border), {18, 2}: opaque(background)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canvas := gg.NewContext(100, 100)
+			if err := DrawFrame(canvas, &tt.frame, tt.matte, drawContent); err != nil {
+				t.Fatal(err)
+			}
+			for point, want := range tt.points {
+				if got := colorAt(canvas, point[0], point[1]); diff(got.R, want.R) > 2 || diff(got.G, want.G) > 2 || diff(got.B, want.B) > 2 || diff(got.A, want.A) > 2 {
+					t.Errorf("color at %v = %v, want %v", point, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDrawFrameShadow(t *testing.T) {
+	background := Color{R: 0xFF, G: 0xFF, B: 0xFF}
+	drawContent := func(content *gg.Context) error {
+		FillBackground(content, &background)
+		return nil
+	}
+
+	outer := gg.NewContext(100, 100)
+	if err := DrawFrame(outer, &FrameParams{Shadow: SHADOW_OUTER, ShadowColor: &Black, ShadowBlur: 9, ShadowOffset: 3}, nil, drawContent); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, a := outer.Image().At(0, 0).RGBA(); a != 0 {
+		t.Errorf("expected transparent top left corner, actual alpha = %d", a>>8)
+	}
+	if _, _, _, a := outer.Image().At(92, 92).RGBA(); a == 0 || a>>8 == 0xFF {
+		t.Errorf("expected translucent shadow at bottom right, actual alpha = %d", a>>8)
+	}
+
+	inset := gg.NewContext(100, 100)
+	if err := DrawFrame(inset, &FrameParams{Shadow: SHADOW_INSET, ShadowColor: &Black, ShadowBlur: 9, ShadowOffset: 3}, nil, drawContent); err != nil {
+		t.Fatal(err)
+	}
+	edge, _, _, _ := inset.Image().At(1, 50).RGBA()
+	centre, _, _, _ := inset.Image().At(50, 50).RGBA()
+	if edge >= centre {
+		t.Errorf("expected darker edge than centre, actual edge = %d, centre = %d", edge>>8, centre>>8)
+	}
+}
+
+func TestFrameParamsValidate(t *testing.T) {
+	tests := []struct {
+		frame FrameParams
+		scale float64
+		want  error
+	}{
+		{frame: FrameParams{BorderWidth: 2, BorderStyle: BORDER_DASHED, Radius: 8, Shadow: SHADOW_OUTER, ShadowBlur: 8, ShadowOffset: 4}},
+		{frame: FrameParams{BorderWidth: math.NaN()}, want: ErrFrameBorderWidth},
+		{frame: FrameParams{BorderWidth: 2, BorderStyle: "dotted"}, want: ErrFrameBorderStyle},
+		{frame: FrameParams{Radius: math.Inf(1)}, want: ErrFrameRadius},
+		{frame: FrameParams{Shadow: "drop"}, want: ErrFrameShadow},
+		{frame: FrameParams{Shadow: SHADOW_INSET, ShadowBlur: math.NaN()}, want: ErrFrameShadowBlur},
+		{frame: FrameParams{Shadow: SHADOW_OUTER, ShadowBlur: 60}, want: ErrFrameShadowBlur},
+		{frame: FrameParams{Shadow: SHADOW_INSET, ShadowOffset: math.Inf(1)}, want: ErrFrameShadowOffset},
+		{frame: FrameParams{Shadow: SHADOW_OUTER, ShadowBlur: 10, ShadowOffset: 90}, want: ErrFrameShadowOffset},
+		// The shadow fits in the image before scaling but not in the scaled image
+		{frame: FrameParams{Shadow: SHADOW_OUTER, ShadowBlur: 40, ShadowOffset: 11}, scale: 0.1, want: ErrFrameShadowOffset},
+	}
+	for _, tt := range tests {
+		if tt.scale == 0 {
+			tt.scale = 1
+		}
+		if got := tt.frame.Validate(100, 100, tt.scale); got != tt.want {
+			t.Errorf("Validate(%+v) = %v, want %v", tt.frame, got, tt.want)
+		}
+	}
+}
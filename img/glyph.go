@@ -0,0 +1,192 @@
+package img
+
+import (
+	"image"
+
+	"github.com/golang/freetype/raster"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Sub-pixel quantization of glyph positions, the same as of [truetype] faces:
+// glyphs are positioned at quarter pixels horizontally and whole pixels vertically.
+const (
+	subPixelBiasX = 32 / 4
+	subPixelMaskX = -64 / 4
+	subPixelBiasY = 32
+	subPixelMaskY = -64
+)
+
+// A glyphFace is a [font.Face] which draws the shaped glyphs of a TrueType font with [gg.Context.DrawString].
+//
+// Each rune of the drawn string is the index of a glyph in glyphs, and each glyph is moved by its offsets and
+// advanced by its advance. Glyphs are rasterized like the glyphs of [truetype] faces, so text drawn with a glyphFace
+// is the same as text drawn by gg with a truetype face of the font.
+//
+// A glyphFace is not safe for concurrent use.
+type glyphFace struct {
+	font       *truetype.Font
+	scale      fixed.Int26_6 // Font size in 26.6 fixed point pixels per em
+	glyphs     []glyphPosition
+	buffer     truetype.GlyphBuf
+	rasterizer *raster.Rasterizer
+	mask       *image.Alpha // Mask of the last rasterized glyph, large enough for any glyph of the font
+}
+
+// A glyphPosition stores a glyph of a TrueType font with its position relative to the pen.
+type glyphPosition struct {
+	index            truetype.Index
+	xOffset, yOffset fixed.Int26_6 // Offsets of the glyph from the pen, y pointing up
+	advance          fixed.Int26_6
+}
+
+// maxGlyphRunes is the number of glyphs drawn by one string, below the first UTF-16 surrogate which is not a valid rune.
+const maxGlyphRunes = 0xD800
+
+// newGlyphFace returns a [glyphFace] for the TrueType font at the size in pixels per em.
+func newGlyphFace(font *truetype.Font, size float64) *glyphFace {
+	face := &glyphFace{font: font, scale: fixed.Int26_6(0.5 + size*64)}
+	bounds := font.Bounds(face.scale)
+	width := (+int(bounds.Max.X+63) >> 6) - (+int(bounds.Min.X) >> 6)
+	height := (-int(bounds.Min.Y-63) >> 6) - (-int(bounds.Max.Y) >> 6)
+	// The rasterizer picks its curve precision from its bounds, so they are the same as of a truetype face
+	face.rasterizer = raster.NewRasterizer(width, height)
+	face.mask = image.NewAlpha(image.Rect(0, 0, width, height))
+	return face
+}
+
+// advance returns the advance of the glyph with given index, rounded like the advances of [truetype] faces.
+func (f *glyphFace) advance(index truetype.Index) fixed.Int26_6 {
+	if err := f.buffer.Load(f.font, f.scale, index, font.HintingNone); err != nil {
+		return 0
+	}
+	return f.buffer.AdvanceWidth
+}
+
+// Close implements [font.Face].
+func (f *glyphFace) Close() error {
+	return nil
+}
+
+// Glyph implements [font.Face] for the glyph at index r of the glyphs of the face.
+func (f *glyphFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	if r < 0 || int(r) >= len(f.glyphs) {
+		return image.Rectangle{}, nil, image.Point{}, 0, false
+	}
+	glyph := f.glyphs[r]
+	dotX := (dot.X + glyph.xOffset + subPixelBiasX) & subPixelMaskX
+	dotY := (dot.Y - glyph.yOffset + subPixelBiasY) & subPixelMaskY
+	bounds, ok := f.rasterize(glyph.index, dotX&0x3F, dotY&0x3F)
+	if !ok {
+		return image.Rectangle{}, nil, image.Point{}, glyph.advance, false
+	}
+	return bounds.Add(image.Point{X: int(dotX >> 6), Y: int(dotY >> 6)}), f.mask, image.Point{}, glyph.advance, true
+}
+
+// GlyphBounds implements [font.Face] for the glyph at index r of the glyphs of the face.
+func (f *glyphFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	if r < 0 || int(r) >= len(f.glyphs) {
+		return fixed.Rectangle26_6{}, 0, false
+	}
+	if err := f.buffer.Load(f.font, f.scale, f.glyphs[r].index, font.HintingNone); err != nil {
+		return fixed.Rectangle26_6{}, 0, false
+	}
+	bounds := f.buffer.Bounds
+	bounds.Min.Y, bounds.Max.Y = -bounds.Max.Y, -bounds.Min.Y
+	return bounds, f.glyphs[r].advance, true
+}
+
+// GlyphAdvance implements [font.Face] for the glyph at index r of the glyphs of the face.
+func (f *glyphFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	if r < 0 || int(r) >= len(f.glyphs) {
+		return 0, false
+	}
+	return f.glyphs[r].advance, true
+}
+
+// Kern implements [font.Face]. Glyph advances already include kerning.
+func (f *glyphFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	return 0
+}
+
+// Metrics implements [font.Face]. Like of [truetype] faces, the height of a line is the font size.
+func (f *glyphFace) Metrics() font.Metrics {
+	return font.Metrics{Height: f.scale}
+}
+
+// rasterize draws the glyph with given index in the mask of the face, with the origin of the glyph at the
+// fractional pixel position fx, fy.
+//
+// It returns the bounds of the glyph in the mask relative to the integer pixel position of the origin,
+// and false if the glyph cannot be loaded or is empty.
+func (f *glyphFace) rasterize(index truetype.Index, fx, fy fixed.Int26_6) (image.Rectangle, bool) {
+	if err := f.buffer.Load(f.font, f.scale, index, font.HintingNone); err != nil {
+		return image.Rectangle{}, false
+	}
+	xmin := int(fx+f.buffer.Bounds.Min.X) >> 6
+	ymin := int(fy-f.buffer.Bounds.Max.Y) >> 6
+	xmax := int(fx+f.buffer.Bounds.Max.X+0x3F) >> 6
+	ymax := int(fy-f.buffer.Bounds.Min.Y+0x3F) >> 6
+	if xmin > xmax || ymin > ymax {
+		return image.Rectangle{}, false
+	}
+	// Glyph points may be left of or above the origin, and the rasterizer clips them
+	fx -= fixed.Int26_6(xmin << 6)
+	fy -= fixed.Int26_6(ymin << 6)
+	f.rasterizer.Clear()
+	for i := range f.mask.Pix {
+		f.mask.Pix[i] = 0
+	}
+	start := 0
+	for _, end := range f.buffer.Ends {
+		f.addContour(f.buffer.Points[start:end], fx, fy)
+		start = end
+	}
+	f.rasterizer.Rasterize(raster.NewAlphaSrcPainter(f.mask))
+	return image.Rect(xmin, ymin, xmax, ymax), true
+}
+
+// addContour adds the quadratic contour of TrueType points to the rasterizer, moved by dx, dy with y pointing down.
+//
+// Two consecutive points off the curve imply a point on the curve in the middle of them.
+func (f *glyphFace) addContour(points []truetype.Point, dx, dy fixed.Int26_6) {
+	if len(points) == 0 {
+		return
+	}
+	point := func(p truetype.Point) fixed.Point26_6 {
+		return fixed.Point26_6{X: dx + p.X, Y: dy - p.Y}
+	}
+	onCurve := func(p truetype.Point) bool {
+		return p.Flags&0x01 != 0
+	}
+	start, others := point(points[0]), points[1:]
+	if !onCurve(points[0]) {
+		last := points[len(points)-1]
+		if onCurve(last) {
+			start, others = point(last), points[:len(points)-1]
+		} else {
+			start = fixed.Point26_6{X: (start.X + point(last).X) / 2, Y: (start.Y + point(last).Y) / 2}
+			others = points
+		}
+	}
+	f.rasterizer.Start(start)
+	previous, previousOn := start, true
+	for _, p := range others {
+		q, on := point(p), onCurve(p)
+		switch {
+		case on && previousOn:
+			f.rasterizer.Add1(q)
+		case on:
+			f.rasterizer.Add2(previous, q)
+		case !previousOn:
+			f.rasterizer.Add2(previous, fixed.Point26_6{X: (previous.X + q.X) / 2, Y: (previous.Y + q.Y) / 2})
+		}
+		previous, previousOn = q, on
+	}
+	if previousOn {
+		f.rasterizer.Add1(start)
+	} else {
+		f.rasterizer.Add2(previous, start)
+	}
+}
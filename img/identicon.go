@@ -0,0 +1,108 @@
+package img
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/cod3rboy/yaps/utils"
+	"github.com/fogleman/gg"
+)
+
+// Limits of identicon grid size
+const (
+	MinIdenticonGrid = 3
+	MaxIdenticonGrid = 16
+)
+
+// An IdenticonParams stores parameters for identicon generation.
+type IdenticonParams struct {
+	Format          string  // Image extension
+	*Size                   // Image Size
+	Seed            string  // Seed string from which the pattern and color are derived
+	Grid            int     // Number of blocks in each row and column
+	Padding         float64 // Padding around the pattern as a fraction of the pattern side, in range [0, 0.5)
+	BackgroundColor *Color  // Color to use for background
+	ForegroundColor *Color  // Color to use for blocks, nil for the color derived from seed
+	Scale           float64 // Value by which to scale Size
+}
+
+// GenerateIdenticon generates a horizontally symmetric block pattern derived from the seed.
+//
+// The pattern is centered and sized to the smaller dimension of the image.
+// The algorithm is fixed so the same seed and grid always produce the same image.
+//
+// It returns [ImageResult], nil when image is generated successfully.
+// If error occurs while generating image, it returns nil, error.
+func GenerateIdenticon(params *IdenticonParams) (*ImageResult, error) {
+	if params.Grid < MinIdenticonGrid || params.Grid > MaxIdenticonGrid {
+		return nil, fmt.Errorf("identicon grid must be in range [%d, %d]", MinIdenticonGrid, MaxIdenticonGrid)
+	}
+	if params.Padding < 0 || params.Padding >= 0.5 {
+		return nil, fmt.Errorf("identicon padding must be in range [0, 0.5)")
+	}
+	w := utils.ScaleDimension(params.Width, params.Scale)
+	h := utils.ScaleDimension(params.Height, params.Scale)
+
+	foreground := IdenticonColor(params.Seed)
+	if params.ForegroundColor != nil {
+		foreground = *params.ForegroundColor
+	}
+
+	canvas := gg.NewContext(w, h)
+	FillBackground(canvas, params.BackgroundColor)
+
+	side := math.Min(float64(w), float64(h))
+	padding := side * params.Padding
+	cell := (side - 2*padding) / float64(params.Grid)
+	x0, y0 := (float64(w)-side)/2+padding, (float64(h)-side)/2+padding
+
+	canvas.SetRGBA255(int(foreground.R), int(foreground.G), int(foreground.B), 0xFF)
+	for row, cells := range IdenticonPattern(params.Seed, params.Grid) {
+		for col, filled := range cells {
+			if !filled {
+				continue
+			}
+			// Snap the block edges to pixels so that adjacent blocks have no seams
+			x1, y1 := math.Round(x0+float64(col)*cell), math.Round(y0+float64(row)*cell)
+			x2, y2 := math.Round(x0+float64(col+1)*cell), math.Round(y0+float64(row+1)*cell)
+			canvas.DrawRectangle(x1, y1, x2-x1, y2-y1)
+		}
+	}
+	canvas.Fill()
+
+	return encodeResult(canvas, params.Format)
+}
+
+// IdenticonPattern returns the grid x grid pattern of filled blocks derived from the seed.
+//
+// The left half of the pattern (including the middle column for odd grid) is read from the bits
+// of the SHA-256 hash of seed, and the right half mirrors it.
+func IdenticonPattern(seed string, grid int) [][]bool {
+	hash := sha256.Sum256([]byte(seed))
+	half := (grid + 1) / 2
+	pattern := make([][]bool, grid)
+	for row := range pattern {
+		pattern[row] = make([]bool, grid)
+		for col := 0; col < half; col++ {
+			index := row*half + col
+			filled := hash[index/8]>>(index%8)&1 == 1
+			pattern[row][col] = filled
+			pattern[row][grid-1-col] = filled
+		}
+	}
+	return pattern
+}
+
+// IdenticonColor returns the block color derived from the seed.
+//
+// The hue is read from the last bytes of the SHA-256 hash of seed, which are never used by the pattern,
+// while saturation and lightness are kept in a range that looks good on light backgrounds.
+func IdenticonColor(seed string) Color {
+	hash := sha256.Sum256([]byte(seed))
+	hue := float64(binary.BigEndian.Uint16(hash[28:30]) % 360)
+	saturation := 0.45 + float64(hash[30])/0xFF*0.2
+	lightness := 0.45 + float64(hash[31])/0xFF*0.15
+	return HSL(hue, saturation, lightness)
+}
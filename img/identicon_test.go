@@ -0,0 +1,102 @@
+package img
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestIdenticonPattern(t *testing.T) {
+	// The pattern must never change across releases for the same seed
+	expected := []string{
+		".###.",
+		"#.#.#",
+		"#####",
+		"..#..",
+		".#.#.",
+	}
+	for row, cells := range IdenticonPattern("yaps", 5) {
+		actual := ""
+		for _, filled := range cells {
+			if filled {
+				actual += "#"
+			} else {
+				actual += "."
+			}
+		}
+		if actual != expected[row] {
+			t.Fatalf("\nexpected pattern =\n%s\nrow %d = %s", strings.Join(expected, "\n"), row, actual)
+		}
+	}
+
+	for grid := MinIdenticonGrid; grid <= MaxIdenticonGrid; grid++ {
+		pattern := IdenticonPattern("symmetry", grid)
+		if len(pattern) != grid {
+			t.Fatalf("grid %d: expected %d rows, actual %d rows", grid, grid, len(pattern))
+		}
+		for row := range pattern {
+			for col := range pattern[row] {
+				if pattern[row][col] != pattern[row][grid-1-col] {
+					t.Fatalf("grid %d: pattern is not symmetric at (%d, %d)", grid, row, col)
+				}
+			}
+		}
+	}
+}
+
+func TestIdenticonColor(t *testing.T) {
+	if actual, expected := IdenticonColor("yaps"), (Color{0xC6, 0x4B, 0x66}); actual != expected {
+		t.Errorf("IdenticonColor() = %+v, want %+v", actual, expected)
+	}
+}
+
+func TestGenerateIdenticon(t *testing.T) {
+	background := Color{0xF0, 0xF0, 0xF0}
+	result, err := GenerateIdenticon(&IdenticonParams{
+		Format:          IMAGE_PNG,
+		Size:            &Size{50, 50},
+		Seed:            "yaps",
+		Grid:            5,
+		Padding:         0,
+		BackgroundColor: &background,
+		Scale:           1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := png.Decode(bytes.NewReader(result.Bytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Bounds() != image.Rect(0, 0, 50, 50) {
+		t.Fatalf("expected bounds = 50x50, actual bounds = %v", decoded.Bounds())
+	}
+	foreground := IdenticonColor("yaps")
+	for row, cells := range IdenticonPattern("yaps", 5) {
+		for col, filled := range cells {
+			// Check the centre pixel of each block
+			r, g, b, _ := decoded.At(col*10+5, row*10+5).RGBA()
+			actual := Color{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8)}
+			expected := background
+			if filled {
+				expected = foreground
+			}
+			if actual != expected {
+				t.Fatalf("block (%d, %d): expected color = %+v, actual color = %+v", row, col, expected, actual)
+			}
+		}
+	}
+
+	invalid := []IdenticonParams{
+		{Format: IMAGE_PNG, Size: &Size{50, 50}, Grid: 2, BackgroundColor: &background, Scale: 1},
+		{Format: IMAGE_PNG, Size: &Size{50, 50}, Grid: 17, BackgroundColor: &background, Scale: 1},
+		{Format: IMAGE_PNG, Size: &Size{50, 50}, Grid: 5, Padding: 0.5, BackgroundColor: &background, Scale: 1},
+	}
+	for _, params := range invalid {
+		if _, err := GenerateIdenticon(&params); err == nil {
+			t.Errorf("expected error for params %+v", params)
+		}
+	}
+}
@@ -11,9 +11,8 @@ import (
 
 	"github.com/cod3rboy/yaps/utils"
 	"github.com/fogleman/gg"
-	"github.com/golang/freetype/truetype"
+	otfont "github.com/go-text/typesetting/font"
 	"github.com/nickalie/go-webpbin"
-	"golang.org/x/image/font/gofont/goregular"
 	"golang.org/x/image/tiff"
 )
 
@@ -69,12 +68,21 @@ type Color struct {
 
 // An ImageParams stores parameters for image generation.
 type ImageParams struct {
-	Format          string  // Image extension
-	*Size                   // Image Size
-	BackgroundColor *Color  // Color to use for background
-	TextColor       *Color  // Color to use for text
-	Scale           float64 // Value by which to scale Size
-	Text            string  // Text to write on the image
+	Format          string         // Image extension
+	*Size                          // Image Size
+	BackgroundColor *Color         // Color to use for background
+	TextColor       *Color         // Color to use for text
+	Scale           float64        // Value by which to scale Size
+	Text            string         // Text to write on the image, ignored when there are text blocks
+	Blocks          []TextBlock    // Blocks of text with their own style, drawn instead of Text
+	Font            string         // Name of the font to write text, see [LoadFonts]
+	Overlay         *OverlayParams // Asset to draw over the image, nil for no overlay
+	Frame           *FrameParams   // Border, corners and shadow of the image, nil for no frame, see [FrameParams.Validate]
+	Shape           string         // Shape of the image e.g. SHAPE_CIRCLE, empty for rectangle
+	Matte           *Color         // Color outside the shape and the frame, nil for transparent (white for formats without transparency)
+	TextLayout      TextLayout     // Orientation of the text
+	Overflow        TextOverflow   // Handling of text which does not fit in the image
+	TextEffects     *TextEffects   // Outline, shadow and box of the text, nil for no effects
 }
 
 // An ImageResult stores data of generated image.
@@ -91,26 +99,122 @@ type ImageResult struct {
 //
 // The actual dimensions of image is calculated by scaling width and height with scale factor in [ImageParams].
 func Generate(params *ImageParams) (*ImageResult, error) {
+	canvas, err := render(params)
+	if err != nil {
+		return nil, err
+	}
+	return encodeResult(canvas, params.Format)
+}
+
+// Render draws an image with given parameters without encoding it.
+//
+// If error occurs while drawing image, it returns nil, error.
+func Render(params *ImageParams) (image.Image, error) {
+	canvas, err := render(params)
+	if err != nil {
+		return nil, err
+	}
+	return canvas.Image(), nil
+}
+
+// render draws the background and text of an image with given parameters on a new canvas.
+func render(params *ImageParams) (*gg.Context, error) {
 	// Scale dimensions by scale factor
 	w := utils.ScaleDimension(params.Width, params.Scale)
 	h := utils.ScaleDimension(params.Height, params.Scale)
 
+	font, err := getFont(params.Font)
+	if err != nil {
+		return nil, err
+	}
+
 	canvas := gg.NewContext(w, h)
 
-	// Background filling
-	FillBackground(canvas, params.BackgroundColor)
-	if err := DrawText(canvas, params.Text, params.TextColor); err != nil {
+	// Shaped images are drawn on a layer which is clipped to the shape
+	layer := canvas
+	if params.Shape != "" {
+		layer = gg.NewContext(w, h)
+	}
+	drawContent := func(content *gg.Context) error {
+		// Background filling
+		FillBackground(content, params.BackgroundColor)
+		bounds := Rect{Width: float64(content.Width()), Height: float64(content.Height())}
+		if params.Shape != "" {
+			shapeBounds, err := ShapeBounds(params.Shape, content.Width(), content.Height())
+			if err != nil {
+				return err
+			}
+			bounds = shapeBounds
+		}
+		drawText := func(layer *gg.Context) error {
+			switch {
+			case len(params.Blocks) > 0:
+				return DrawTextBlocks(layer, params.Blocks, bounds)
+			case !params.TextLayout.IsHorizontal():
+				DrawTextLayout(layer, params.Text, params.TextColor, font, bounds, params.TextLayout)
+			case params.Shape != "":
+				DrawTextInRect(layer, params.Text, params.TextColor, font, bounds, params.Overflow)
+			case params.Overflow != TextOverflow{}:
+				DrawTextWithOverflow(layer, params.Text, params.TextColor, font, params.Overflow)
+			default:
+				DrawText(layer, params.Text, params.TextColor, font)
+			}
+			return nil
+		}
+		var err error
+		if params.TextEffects != nil {
+			err = DrawTextEffects(content, params.TextEffects.scaled(params.Scale), drawText)
+		} else {
+			err = drawText(content)
+		}
+		if err != nil {
+			return err
+		}
+		if params.Overlay != nil {
+			return DrawOverlay(content, params.Overlay)
+		}
+		return nil
+	}
+	matte := params.Matte
+	if matte == nil && !SupportsTransparency(params.Format) {
+		matte = &matteColor
+	}
+	if params.Frame != nil {
+		err = DrawFrame(layer, params.Frame.scaled(params.Scale), matte, drawContent)
+	} else {
+		err = drawContent(layer)
+	}
+	if err != nil {
 		return nil, err
 	}
 
+	if params.Shape != "" {
+		if matte != nil {
+			FillBackground(canvas, matte)
+		}
+		if err := DrawShape(canvas, params.Shape); err != nil {
+			return nil, err
+		}
+		canvas.Clip()
+		canvas.DrawImage(layer.Image(), 0, 0)
+		canvas.ResetClip()
+	}
+
+	return canvas, nil
+}
+
+// encodeResult encodes the canvas for given image format into an [ImageResult].
+//
+// If an error occurs while encoding, it returns nil, error.
+func encodeResult(canvas *gg.Context, format string) (*ImageResult, error) {
 	// Determine mime type
-	mimeType, exists := mimeTypes[params.Format]
+	mimeType, exists := mimeTypes[format]
 	if !exists {
-		return nil, fmt.Errorf("mime type not found for format %s", params.Format)
+		return nil, fmt.Errorf("mime type not found for format %s", format)
 	}
 
 	// Encode image
-	imgBytes, err := encode(canvas, params.Format)
+	imgBytes, err := encode(canvas, format)
 	if err != nil {
 		return nil, err
 	}
@@ -128,22 +232,22 @@ func FillBackground(canvas *gg.Context, color *Color) {
 	canvas.Clear()
 }
 
-// DrawText draws the given text on the canvas with given color.
+// DrawText draws the given text on the canvas with given color and font.
 //
 // Dynamic font size is used to draw text and is calcuated by canvas height * [PX_TO_PT] * 0.2.
 //
 // The text is anchored at the image centre.
 // It also wraps around when overflows the canvas width.
-func DrawText(canvas *gg.Context, text string, color *Color) error {
-	canvas.SetRGBA255(int(color.R), int(color.G), int(color.B), 0xFF)
-	font, err := truetype.Parse(goregular.TTF)
-	if err != nil {
-		return err
-	}
-	fontFace := truetype.NewFace(font, &truetype.Options{Size: float64(canvas.Height()) * PX_TO_PT * 0.2})
-	canvas.SetFontFace(fontFace)
-	canvas.DrawStringWrapped(text, float64(canvas.Width()/2), float64(canvas.Height())/2, 0.5, 0.5, float64(canvas.Width())*0.8, 1, gg.AlignCenter)
-	return nil
+func DrawText(canvas *gg.Context, text string, color *Color, font *otfont.Font) {
+	drawText(canvas, text, color, font, float64(canvas.Height())*PX_TO_PT*0.2, float64(canvas.Width())*0.8)
+}
+
+// drawText draws the given text at the canvas centre with given color, font and font size in points.
+// The text is shaped and wraps around when it overflows maxWidth, see [textShaper].
+func drawText(canvas *gg.Context, text string, color *Color, font *otfont.Font, fontSize, maxWidth float64) {
+	shaper := newTextShaper(font)
+	shaper.setSize(fontSize)
+	shaper.drawLines(canvas, shaper.wrap(text, maxWidth), color, float64(canvas.Width()/2), float64(canvas.Height())/2, 1)
 }
 
 // encode converts the canvas into bytes for given image format.
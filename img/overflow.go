@@ -0,0 +1,106 @@
+package img
+
+import (
+	"math"
+
+	"github.com/fogleman/gg"
+	otfont "github.com/go-text/typesetting/font"
+)
+
+// Constants for text overflow policies
+const (
+	OVERFLOW_VISIBLE  = "visible"
+	OVERFLOW_CLIP     = "clip"
+	OVERFLOW_ELLIPSIS = "ellipsis"
+	OVERFLOW_SHRINK   = "shrink"
+)
+
+// A TextOverflow stores how text which does not fit in its bounds is handled.
+type TextOverflow struct {
+	MaxLines  int     // Maximum number of lines, 0 for no limit
+	Policy    string  // Overflow policy e.g. OVERFLOW_ELLIPSIS, empty for the default policy of the drawing function
+	Hyphenate bool    // Whether words wider than a line are broken with a hyphen
+	MinSize   float64 // Font size in points below which OVERFLOW_SHRINK ends the text with an ellipsis instead, 0 for no limit
+}
+
+// DrawTextWithOverflow draws the given text on the canvas with given color and font like [DrawText],
+// and handles the overflow of the text.
+//
+// The text box is 80% of the canvas width and height. The overflow policies are:
+//   - [OVERFLOW_VISIBLE], the default, draws all the lines even if they spill out of the text box.
+//   - [OVERFLOW_CLIP] drops the lines after MaxLines or which do not fit in the text box, and cuts the text at its edges.
+//   - [OVERFLOW_ELLIPSIS] drops the same lines as [OVERFLOW_CLIP] and ends the last line with an ellipsis.
+//   - [OVERFLOW_SHRINK] shrinks the font size until the text fits in MaxLines lines and the text box.
+//     The font size does not go below MinSize, at which the text is ellipsized like [OVERFLOW_ELLIPSIS].
+//
+// MaxLines is ignored by [OVERFLOW_VISIBLE].
+func DrawTextWithOverflow(canvas *gg.Context, text string, color *Color, font *otfont.Font, overflow TextOverflow) {
+	width, height := float64(canvas.Width()), float64(canvas.Height())
+	bounds := Rect{X: width * 0.1, Y: height * 0.1, Width: width * 0.8, Height: height * 0.8}
+	if overflow.Policy == "" {
+		overflow.Policy = OVERFLOW_VISIBLE
+	}
+	drawTextBox(canvas, text, color, font, height*PX_TO_PT*0.2, bounds, 1, overflow)
+}
+
+// drawTextBox draws the text with given color and font centered in bounds with given line spacing.
+//
+// The text is wrapped at the width of bounds, starting with the given font size in points,
+// and its overflow is handled by the policy, see [DrawTextWithOverflow].
+func drawTextBox(canvas *gg.Context, text string, color *Color, font *otfont.Font, fontSize float64, bounds Rect, lineSpacing float64, overflow TextOverflow) {
+	shaper := newTextShaper(font)
+	shaper.hyphenate = overflow.Hyphenate
+	lines := fitText(shaper, text, fontSize, bounds, lineSpacing, overflow)
+	if overflow.Policy == OVERFLOW_CLIP {
+		canvas.Push()
+		defer canvas.Pop()
+		canvas.DrawRectangle(bounds.X, bounds.Y, bounds.Width, bounds.Height)
+		canvas.Clip()
+	}
+	shaper.drawLines(canvas, lines, color, bounds.X+bounds.Width/2, bounds.Y+bounds.Height/2, lineSpacing)
+}
+
+// fitText wraps the text with the shaper in bounds according to the overflow policy.
+//
+// It sets the font size of the shaper, which is the given font size unless the policy shrinks the text.
+func fitText(shaper *textShaper, text string, fontSize float64, bounds Rect, lineSpacing float64, overflow TextOverflow) []textLine {
+	switch overflow.Policy {
+	case OVERFLOW_SHRINK:
+		var lines []textLine
+		for ; fontSize > 1; fontSize *= 0.9 {
+			shaper.setSize(fontSize)
+			lines = shaper.wrap(text, bounds.Width)
+			if fitsBounds(shaper, lines, bounds, lineSpacing) && (overflow.MaxLines == 0 || len(lines) <= overflow.MaxLines) {
+				break
+			}
+			if fontSize*0.9 < overflow.MinSize {
+				overflow.Policy = OVERFLOW_ELLIPSIS
+				return fitText(shaper, text, fontSize, bounds, lineSpacing, overflow)
+			}
+		}
+		return lines
+	case OVERFLOW_CLIP, OVERFLOW_ELLIPSIS:
+		shaper.setSize(fontSize)
+		// Lines of the block which fit in the bounds height, at least one
+		lineHeight := shaper.height * lineSpacing
+		shaper.maxLines = int(math.Max(1, math.Floor((bounds.Height+lineHeight-shaper.height)/lineHeight)))
+		if overflow.MaxLines > 0 && overflow.MaxLines < shaper.maxLines {
+			shaper.maxLines = overflow.MaxLines
+		}
+		shaper.ellipsis = overflow.Policy == OVERFLOW_ELLIPSIS
+		return shaper.wrap(text, bounds.Width)
+	default:
+		shaper.setSize(fontSize)
+		return shaper.wrap(text, bounds.Width)
+	}
+}
+
+// fitsBounds returns true if the lines of the shaper with given line spacing fit in the bounds otherwise it returns false.
+func fitsBounds(shaper *textShaper, lines []textLine, bounds Rect, lineSpacing float64) bool {
+	for _, line := range lines {
+		if line.width > bounds.Width {
+			return false
+		}
+	}
+	return shaper.blockHeight(len(lines), lineSpacing) <= bounds.Height
+}
@@ -0,0 +1,96 @@
+package img
+
+import (
+	"testing"
+
+	"github.com/go-text/typesetting/shaping"
+)
+
+func TestFitText(t *testing.T) {
+	font, err := getFont(DefaultFont)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := "Ultra-comfortable ergonomic office chair with adjustable lumbar support and breathable mesh backrest"
+	bounds := Rect{X: 0, Y: 0, Width: 120, Height: 120}
+	tests := []struct {
+		name     string
+		overflow TextOverflow
+		check    func(t *testing.T, shaper *textShaper, lines []textLine)
+	}{
+		{name: "Visible keeps all lines", overflow: TextOverflow{MaxLines: 2, Policy: OVERFLOW_VISIBLE}, check: func(t *testing.T, shaper *textShaper, lines []textLine) {
+			if fitsBounds(shaper, lines, bounds, 1) {
+				t.Error("expected lines to overflow bounds")
+			}
+		}},
+		{name: "Clip drops lines below bounds", overflow: TextOverflow{Policy: OVERFLOW_CLIP}, check: func(t *testing.T, shaper *textShaper, lines []textLine) {
+			if want := int(bounds.Height / shaper.height); len(lines) != want {
+				t.Errorf("lines = %d, want %d", len(lines), want)
+			}
+		}},
+		{name: "Ellipsis ends last line", overflow: TextOverflow{MaxLines: 2, Policy: OVERFLOW_ELLIPSIS}, check: func(t *testing.T, shaper *textShaper, lines []textLine) {
+			if len(lines) != 2 {
+				t.Fatalf("lines = %d, want 2", len(lines))
+			}
+			if !endsWith(lines[1].runs, ellipsisRune) {
+				t.Error("expected last line to end with an ellipsis")
+			}
+		}},
+		{name: "Shrink fits max lines", overflow: TextOverflow{MaxLines: 3, Policy: OVERFLOW_SHRINK}, check: func(t *testing.T, shaper *textShaper, lines []textLine) {
+			if len(lines) > 3 || !fitsBounds(shaper, lines, bounds, 1) {
+				t.Errorf("expected at most 3 lines in bounds, got %d lines", len(lines))
+			}
+			if shaper.size >= 30 {
+				t.Errorf("expected font size to shrink, got %f", shaper.size)
+			}
+		}},
+		{name: "Shrink ellipsizes at min size", overflow: TextOverflow{MaxLines: 3, Policy: OVERFLOW_SHRINK, MinSize: 20}, check: func(t *testing.T, shaper *textShaper, lines []textLine) {
+			if shaper.size < 20 || shaper.size >= 30 {
+				t.Errorf("expected font size in [20, 30), got %f", shaper.size)
+			}
+			if len(lines) > 3 || !endsWith(lines[len(lines)-1].runs, ellipsisRune) {
+				t.Errorf("expected at most 3 lines ending with an ellipsis, got %d lines", len(lines))
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shaper := newTextShaper(font)
+			tt.check(t, shaper, fitText(shaper, text, 30, bounds, 1, tt.overflow))
+		})
+	}
+}
+
+func TestTextShaperHyphenate(t *testing.T) {
+	font, err := getFont(DefaultFont)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shaper := newTextShaper(font)
+	shaper.setSize(20)
+	shaper.hyphenate = true
+	lines := shaper.wrap("Donaudampfschifffahrtsgesellschaft", 150)
+	if len(lines) < 2 {
+		t.Fatalf("wrap() lines = %d, want at least 2", len(lines))
+	}
+	for i, line := range lines {
+		if hyphenated := endsWith(line.runs, hyphenRune); hyphenated != (i < len(lines)-1) {
+			t.Errorf("line %d hyphenated = %t", i, hyphenated)
+		}
+		if line.width > 150 {
+			t.Errorf("line %d width = %f, want at most 150", i, line.width)
+		}
+	}
+}
+
+// endsWith returns true if the last glyph of the line in visual order is the glyph of given character.
+func endsWith(line shaping.Line, char rune) bool {
+	last := line[0]
+	for _, run := range line {
+		if run.VisualIndex > last.VisualIndex {
+			last = run
+		}
+	}
+	glyph, ok := last.Face.NominalGlyph(char)
+	return ok && len(last.Glyphs) > 0 && last.Glyphs[len(last.Glyphs)-1].GlyphID == glyph
+}
@@ -0,0 +1,216 @@
+package img
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fogleman/gg"
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+	"golang.org/x/image/draw"
+)
+
+// Constants for overlay positions
+const (
+	POSITION_CENTER       = "center"
+	POSITION_TOP_LEFT     = "top-left"
+	POSITION_TOP          = "top"
+	POSITION_TOP_RIGHT    = "top-right"
+	POSITION_LEFT         = "left"
+	POSITION_RIGHT        = "right"
+	POSITION_BOTTOM_LEFT  = "bottom-left"
+	POSITION_BOTTOM       = "bottom"
+	POSITION_BOTTOM_RIGHT = "bottom-right"
+)
+
+// Mapping of an overlay position to its anchor, as fractions of the free space in each direction
+var positionAnchors = map[string][2]float64{
+	POSITION_CENTER:       {0.5, 0.5},
+	POSITION_TOP_LEFT:     {0, 0},
+	POSITION_TOP:          {0.5, 0},
+	POSITION_TOP_RIGHT:    {1, 0},
+	POSITION_LEFT:         {0, 0.5},
+	POSITION_RIGHT:        {1, 0.5},
+	POSITION_BOTTOM_LEFT:  {0, 1},
+	POSITION_BOTTOM:       {0.5, 1},
+	POSITION_BOTTOM_RIGHT: {1, 1},
+}
+
+// Margin between the overlay and the canvas edges as a fraction of the smaller canvas dimension
+const overlayMargin = 0.04
+
+// Gap between tiled overlays as a fraction of the overlay width
+const overlayTileGap = 0.5
+
+// Minimum width of tiled overlays as a fraction of the larger canvas dimension, which bounds the number of tiles
+const overlayTileMinSize = 0.05
+
+// Extensions of the asset files loaded by [LoadAssets]
+const (
+	assetExtensionPNG = ".png"
+	assetExtensionSVG = ".svg"
+)
+
+// An asset is an image which can be drawn over generated images. SVG assets are rasterized at the drawn size.
+type asset struct {
+	raster image.Image // Decoded PNG asset
+	svg    []byte      // Source of SVG asset
+	ratio  float64     // Width / height ratio of the asset
+}
+
+// Mapping of an asset name to its asset.
+var assets = map[string]*asset{}
+
+// An OverlayParams stores parameters to draw an asset over the image.
+type OverlayParams struct {
+	Asset    string  // Name of the asset, see [LoadAssets]
+	Position string  // Position of the asset, one of the POSITION constants. Ignored when tiled.
+	Size     float64 // Width of the asset as a percentage of the image width, in range (0, 100]
+	Opacity  float64 // Opacity of the asset, in range [0, 1]
+	Tile     bool    // Whether the asset is repeated over the whole image
+}
+
+// LoadAssets registers all the PNG (.png) and SVG (.svg) files present in directory dir as overlay assets.
+//
+// Each asset is registered with the file name without extension e.g. logo.svg is registered as logo.
+// If an error occurs while reading or parsing an asset file, it returns that error.
+func LoadAssets(dir string) error {
+	for _, extension := range []string{assetExtensionPNG, assetExtensionSVG} {
+		paths, err := filepath.Glob(filepath.Join(dir, "*"+extension))
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			loaded, err := parseAsset(data, extension)
+			if err != nil {
+				return fmt.Errorf("failed to parse asset %s: %w", path, err)
+			}
+			assets[strings.TrimSuffix(filepath.Base(path), extension)] = loaded
+		}
+	}
+	return nil
+}
+
+// parseAsset parses the asset data of a file with given extension.
+func parseAsset(data []byte, extension string) (*asset, error) {
+	if extension == assetExtensionSVG {
+		icon, err := oksvg.ReadIconStream(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if icon.ViewBox.W <= 0 || icon.ViewBox.H <= 0 {
+			return nil, errors.New("svg must have a view box")
+		}
+		return &asset{svg: data, ratio: icon.ViewBox.W / icon.ViewBox.H}, nil
+	}
+	raster, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	bounds := raster.Bounds()
+	if bounds.Empty() {
+		return nil, errors.New("image must not be empty")
+	}
+	return &asset{raster: raster, ratio: float64(bounds.Dx()) / float64(bounds.Dy())}, nil
+}
+
+// HasAsset returns true if an asset is registered with given name otherwise it returns false.
+func HasAsset(name string) bool {
+	_, exists := assets[name]
+	return exists
+}
+
+// render draws the asset at given size into a new image.
+func (a *asset) render(width, height int) (image.Image, error) {
+	rendered := image.NewRGBA(image.Rect(0, 0, width, height))
+	if a.svg == nil {
+		draw.CatmullRom.Scale(rendered, rendered.Bounds(), a.raster, a.raster.Bounds(), draw.Src, nil)
+		return rendered, nil
+	}
+	// SVG icons are parsed again for each render because drawing mutates the icon
+	icon, err := oksvg.ReadIconStream(bytes.NewReader(a.svg))
+	if err != nil {
+		return nil, err
+	}
+	icon.SetTarget(0, 0, float64(width), float64(height))
+	scanner := rasterx.NewScannerGV(width, height, rendered, rendered.Bounds())
+	icon.Draw(rasterx.NewDasher(width, height, scanner), 1)
+	return rendered, nil
+}
+
+// DrawOverlay draws the overlay asset over the canvas.
+//
+// The asset keeps its aspect ratio and is placed at the position with a margin from the canvas edges,
+// or repeated over the whole canvas when tiled. Tiles are at least [overlayTileMinSize] of the larger canvas dimension
+// wide. A tall asset is shrunk so that it is no taller than the canvas.
+// If the asset is not registered or the parameters are out of range, it returns an error.
+func DrawOverlay(canvas *gg.Context, overlay *OverlayParams) error {
+	loaded, exists := assets[overlay.Asset]
+	if !exists {
+		return fmt.Errorf("asset not found with name %s", overlay.Asset)
+	}
+	anchor, exists := positionAnchors[overlay.Position]
+	if !exists && !overlay.Tile {
+		return fmt.Errorf("unknown overlay position %s", overlay.Position)
+	}
+	if !(overlay.Size > 0 && overlay.Size <= 100) {
+		return errors.New("overlay size must be in range (0, 100]")
+	}
+	if !(overlay.Opacity >= 0 && overlay.Opacity <= 1) {
+		return errors.New("overlay opacity must be in range [0, 1]")
+	}
+
+	w, h := canvas.Width(), canvas.Height()
+	assetW := int(math.Max(1, math.Round(float64(w)*overlay.Size/100)))
+	if overlay.Tile {
+		assetW = int(math.Max(float64(assetW), math.Round(math.Max(float64(w), float64(h))*overlayTileMinSize)))
+	}
+	assetH := int(math.Max(1, math.Round(float64(assetW)/loaded.ratio)))
+	if assetH > h {
+		// Size only limits the width, so a tall asset is shrunk to the canvas height
+		assetW, assetH = int(math.Max(1, math.Round(float64(h)*loaded.ratio))), h
+	}
+	rendered, err := loaded.render(assetW, assetH)
+	if err != nil {
+		return err
+	}
+
+	dst, ok := canvas.Image().(draw.Image)
+	if !ok {
+		return errors.New("canvas image is not drawable")
+	}
+	mask := image.NewUniform(color.Alpha{A: uint8(math.Round(overlay.Opacity * 0xFF))})
+	drawAt := func(x, y int) {
+		rect := image.Rect(x, y, x+assetW, y+assetH)
+		draw.DrawMask(dst, rect, rendered, image.Point{}, mask, image.Point{}, draw.Over)
+	}
+
+	if overlay.Tile {
+		stepX, stepY := assetW+int(float64(assetW)*overlayTileGap), assetH+int(float64(assetW)*overlayTileGap)
+		// Start the tile grid from a tile at the canvas centre so that tiles are cut equally at opposite edges
+		offsetX, offsetY := (w-assetW)/2%stepX-stepX, (h-assetH)/2%stepY-stepY
+		for y := offsetY; y < h; y += stepY {
+			for x := offsetX; x < w; x += stepX {
+				drawAt(x, y)
+			}
+		}
+		return nil
+	}
+
+	margin := math.Min(float64(w), float64(h)) * overlayMargin
+	x := margin + (float64(w)-2*margin-float64(assetW))*anchor[0]
+	y := margin + (float64(h)-2*margin-float64(assetH))*anchor[1]
+	drawAt(int(math.Round(x)), int(math.Round(y)))
+	return nil
+}
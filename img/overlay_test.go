@@ -0,0 +1,123 @@
+package img
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fogleman/gg"
+)
+
+const testSVGAsset = `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 20 10"><rect width="20" height="10" fill="#0000ff"/></svg>`
+
+func loadTestAssets(t *testing.T) {
+	dir := t.TempDir()
+	red := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	draw.Draw(red, red.Bounds(), image.NewUniform(color.NRGBA{R: 0xFF, A: 0xFF}), image.Point{}, draw.Src)
+	buffer := new(bytes.Buffer)
+	if err := png.Encode(buffer, red); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "red.png"), buffer.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "blue.svg"), []byte(testSVGAsset), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadAssets(dir); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadAssets(t *testing.T) {
+	loadTestAssets(t)
+	for name, want := range map[string]bool{"red": true, "blue": true, "green": false} {
+		if got := HasAsset(name); got != want {
+			t.Errorf("HasAsset(%q) = %v, want %v", name, got, want)
+		}
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.png"), []byte("not a png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadAssets(dir); err == nil {
+		t.Error("expected error for invalid asset file")
+	}
+}
+
+func TestDrawOverlay(t *testing.T) {
+	loadTestAssets(t)
+
+	tests := []struct {
+		name    string
+		overlay OverlayParams
+		points  map[image.Point]color.RGBA
+	}{
+		{
+			name:    "PNG at centre",
+			overlay: OverlayParams{Asset: "red", Position: POSITION_CENTER, Size: 20, Opacity: 1},
+			points:  map[image.Point]color.RGBA{{100, 50}: {R: 0xFF, A: 0xFF}, {5, 5}: {R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}},
+		},
+		{
+			name:    "SVG at bottom right with margin",
+			overlay: OverlayParams{Asset: "blue", Position: POSITION_BOTTOM_RIGHT, Size: 40, Opacity: 1},
+			points:  map[image.Point]color.RGBA{{190, 90}: {B: 0xFF, A: 0xFF}, {198, 98}: {R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}},
+		},
+		{
+			name:    "Half opacity",
+			overlay: OverlayParams{Asset: "red", Position: POSITION_TOP_LEFT, Size: 20, Opacity: 0.5},
+			points:  map[image.Point]color.RGBA{{10, 10}: {R: 0xFF, G: 0x7F, B: 0x7F, A: 0xFF}},
+		},
+		{
+			name:    "Tall asset shrinks to canvas height",
+			overlay: OverlayParams{Asset: "red", Position: POSITION_CENTER, Size: 100, Opacity: 1},
+			points:  map[image.Point]color.RGBA{{100, 50}: {R: 0xFF, A: 0xFF}, {20, 50}: {R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}},
+		},
+		{
+			name:    "Tiled",
+			overlay: OverlayParams{Asset: "red", Size: 10, Opacity: 1, Tile: true},
+			points:  map[image.Point]color.RGBA{{100, 50}: {R: 0xFF, A: 0xFF}, {130, 50}: {R: 0xFF, A: 0xFF}, {70, 80}: {R: 0xFF, A: 0xFF}},
+		},
+		{
+			name:    "Tiles are not smaller than the minimum size",
+			overlay: OverlayParams{Asset: "red", Size: 0.01, Opacity: 1, Tile: true},
+			points:  map[image.Point]color.RGBA{{100, 50}: {R: 0xFF, A: 0xFF}, {107, 50}: {R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canvas := gg.NewContext(200, 100)
+			FillBackground(canvas, &White)
+			if err := DrawOverlay(canvas, &tt.overlay); err != nil {
+				t.Fatal(err)
+			}
+			for point, want := range tt.points {
+				r, g, b, a := canvas.Image().At(point.X, point.Y).RGBA()
+				got := color.RGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: uint8(a >> 8)}
+				if diff(got.R, want.R) > 2 || diff(got.G, want.G) > 2 || diff(got.B, want.B) > 2 {
+					t.Errorf("color at %v = %v, want %v", point, got, want)
+				}
+			}
+		})
+	}
+
+	for _, overlay := range []OverlayParams{
+		{Asset: "green", Position: POSITION_CENTER, Size: 20, Opacity: 1},
+		{Asset: "red", Position: "middle", Size: 20, Opacity: 1},
+		{Asset: "red", Position: POSITION_CENTER, Size: 0, Opacity: 1},
+		{Asset: "red", Position: POSITION_CENTER, Size: 20, Opacity: 2},
+		{Asset: "red", Position: POSITION_CENTER, Size: math.NaN(), Opacity: 1},
+		{Asset: "red", Position: POSITION_CENTER, Size: 20, Opacity: math.NaN()},
+	} {
+		if err := DrawOverlay(gg.NewContext(10, 10), &overlay); err == nil {
+			t.Errorf("expected error for overlay %+v", overlay)
+		}
+	}
+}
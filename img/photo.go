@@ -0,0 +1,194 @@
+package img
+
+import (
+	"fmt"
+	"image"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cod3rboy/yaps/utils"
+	"github.com/fogleman/gg"
+	"golang.org/x/image/draw"
+)
+
+// Extensions of the photo files loaded by [LoadPhotos]
+var photoExtensions = []string{".jpg", ".jpeg", ".png"}
+
+// Largest width and height of the photos kept in memory, larger photos are downscaled by [LoadPhotos]
+const maxPhotoDimension = 2048
+
+// Mapping of a photo tag to its decoded photos, sorted by path. The empty tag refers to all photos.
+var photos = map[string][]image.Image{}
+
+// A PhotoParams stores parameters for photo generation.
+type PhotoParams struct {
+	Format    string         // Image extension
+	*Size                    // Image Size
+	Photo     image.Image    // Photo which is cropped and resized to Size
+	Crop      string         // Crop strategy, one of CROP_CENTER, CROP_ENTROPY or CROP_ATTENTION
+	Scale     float64        // Value by which to scale Size
+	Text      string         // Text to write on the photo, empty for no text
+	TextColor *Color         // Color to use for text
+	Font      string         // Name of the font to write text, see [LoadFonts]
+	Overlay   *OverlayParams // Asset to draw over the photo, nil for no overlay
+	Effects   *TextEffects   // Outline, shadow and box of the text, nil for no effects
+}
+
+// LoadPhotos registers all the JPEG and PNG photos present in directory dir and its subdirectories.
+//
+// Each photo is tagged with the name of the top level subdirectory which contains it e.g. nature/lake.jpg
+// is tagged nature. Photos directly inside dir have no tag. All photos are available with the empty tag.
+//
+// The photos are decoded once and kept in memory, downscaled to at most [maxPhotoDimension] pixels wide and high.
+// If an error occurs while reading the directory or decoding a photo, it returns that error.
+func LoadPhotos(dir string) error {
+	loaded := map[string][]string{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !isPhotoFile(path) {
+			return nil
+		}
+		relative, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if parts := strings.Split(filepath.ToSlash(relative), "/"); len(parts) > 1 {
+			loaded[parts[0]] = append(loaded[parts[0]], path)
+		}
+		loaded[""] = append(loaded[""], path)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	decoded := map[string]image.Image{}
+	for _, path := range loaded[""] {
+		photo, err := decodePhoto(path)
+		if err != nil {
+			return err
+		}
+		decoded[path] = photo
+	}
+	tagged := make(map[string][]image.Image, len(loaded))
+	for tag, paths := range loaded {
+		sort.Strings(paths)
+		for _, path := range paths {
+			tagged[tag] = append(tagged[tag], decoded[path])
+		}
+	}
+	photos = tagged
+	return nil
+}
+
+// decodePhoto decodes the photo file at path and downscales it to fit in [maxPhotoDimension].
+//
+// If an error occurs while reading or decoding the photo, it returns nil, error.
+func decodePhoto(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	photo, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode photo %s: %w", path, err)
+	}
+	w, h := photo.Bounds().Dx(), photo.Bounds().Dy()
+	if w <= maxPhotoDimension && h <= maxPhotoDimension {
+		return photo, nil
+	}
+	factor := maxPhotoDimension / math.Max(float64(w), float64(h))
+	downscaled := image.NewRGBA(image.Rect(0, 0, int(math.Max(1, math.Round(float64(w)*factor))), int(math.Max(1, math.Round(float64(h)*factor)))))
+	draw.CatmullRom.Scale(downscaled, downscaled.Bounds(), photo, photo.Bounds(), draw.Src, nil)
+	return downscaled, nil
+}
+
+// isPhotoFile returns true if the file extension is one of photoExtensions.
+func isPhotoFile(path string) bool {
+	extension := strings.ToLower(filepath.Ext(path))
+	for _, photoExtension := range photoExtensions {
+		if extension == photoExtension {
+			return true
+		}
+	}
+	return false
+}
+
+// PhotoCount returns the number of photos with given tag. The empty tag counts all photos.
+func PhotoCount(tag string) int {
+	return len(photos[tag])
+}
+
+// PhotoTags returns the sorted tags of the loaded photos.
+func PhotoTags() []string {
+	tags := []string{}
+	for tag := range photos {
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// OpenPhoto returns the photo at index in the sorted photos with given tag.
+//
+// The photo is decoded by [LoadPhotos] and shared by all requests, so it must not be modified.
+// If index is out of range, it returns nil, error.
+func OpenPhoto(tag string, index int) (image.Image, error) {
+	tagged := photos[tag]
+	if index < 0 || index >= len(tagged) {
+		return nil, fmt.Errorf("photo index %d out of range for tag %q", index, tag)
+	}
+	return tagged[index], nil
+}
+
+// GeneratePhoto generates an image by cropping the photo to the aspect ratio of the image with the crop strategy
+// and resizing it to the image dimensions. The text is written at the centre when it is not empty.
+//
+// It returns [ImageResult], nil when image is generated successfully.
+// If error occurs while generating image, it returns nil, error.
+func GeneratePhoto(params *PhotoParams) (*ImageResult, error) {
+	w := utils.ScaleDimension(params.Width, params.Scale)
+	h := utils.ScaleDimension(params.Height, params.Scale)
+
+	crop, err := CropRect(params.Photo, w, h, params.Crop)
+	if err != nil {
+		return nil, err
+	}
+	resized := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(resized, resized.Bounds(), params.Photo, crop, draw.Src, nil)
+
+	canvas := gg.NewContextForRGBA(resized)
+	if params.Text != "" {
+		font, err := getFont(params.Font)
+		if err != nil {
+			return nil, err
+		}
+		drawText := func(layer *gg.Context) error {
+			DrawText(layer, params.Text, params.TextColor, font)
+			return nil
+		}
+		if params.Effects != nil {
+			err = DrawTextEffects(canvas, params.Effects.scaled(params.Scale), drawText)
+		} else {
+			err = drawText(canvas)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if params.Overlay != nil {
+		if err := DrawOverlay(canvas, params.Overlay); err != nil {
+			return nil, err
+		}
+	}
+
+	return encodeResult(canvas, params.Format)
+}
@@ -0,0 +1,104 @@
+package img
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadPhotos(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"nature/lake.png", "nature/forest/tree.png", "people/jane.png", "city.png", "notes.txt"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		buffer := new(bytes.Buffer)
+		if err := png.Encode(buffer, image.NewNRGBA(image.Rect(0, 0, 40, 30))); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, buffer.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() { photos = map[string][]image.Image{} }()
+	if err := LoadPhotos(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := PhotoTags(), []string{"nature", "people"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PhotoTags() = %v, want %v", got, want)
+	}
+	for tag, want := range map[string]int{"": 4, "nature": 2, "people": 1, "cars": 0} {
+		if got := PhotoCount(tag); got != want {
+			t.Errorf("PhotoCount(%q) = %d, want %d", tag, got, want)
+		}
+	}
+
+	photo, err := OpenPhoto("nature", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := photo.Bounds().Size(); size.X != 40 || size.Y != 30 {
+		t.Errorf("expected photo size = 40x30, actual size = %dx%d", size.X, size.Y)
+	}
+	if _, err := OpenPhoto("people", 1); err == nil {
+		t.Error("expected error for index out of range")
+	}
+
+	result, err := GeneratePhoto(&PhotoParams{
+		Format:    IMAGE_PNG,
+		Size:      &Size{Width: 20, Height: 20},
+		Photo:     photo,
+		Crop:      CROP_CENTER,
+		Scale:     2,
+		Text:      "Photo",
+		TextColor: &White,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := png.Decode(bytes.NewReader(result.Bytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := decoded.Bounds().Size(); size.X != 40 || size.Y != 40 {
+		t.Errorf("expected image size = 40x40, actual size = %dx%d", size.X, size.Y)
+	}
+}
+
+func TestLoadPhotosDecode(t *testing.T) {
+	dir := t.TempDir()
+	buffer := new(bytes.Buffer)
+	if err := png.Encode(buffer, image.NewNRGBA(image.Rect(0, 0, 2*maxPhotoDimension, maxPhotoDimension/2))); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "wide.png"), buffer.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { photos = map[string][]image.Image{} }()
+	if err := LoadPhotos(dir); err != nil {
+		t.Fatal(err)
+	}
+	photo, err := OpenPhoto("", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := photo.Bounds().Size(); size.X != maxPhotoDimension || size.Y != maxPhotoDimension/4 {
+		t.Errorf("expected photo size = %dx%d, actual size = %dx%d", maxPhotoDimension, maxPhotoDimension/4, size.X, size.Y)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.jpg"), []byte("not a photo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadPhotos(dir); err == nil {
+		t.Error("expected error for photo which cannot be decoded")
+	}
+	if PhotoCount("") != 1 {
+		t.Errorf("expected loaded photos to be unchanged on error")
+	}
+}
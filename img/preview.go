@@ -0,0 +1,38 @@
+package img
+
+import (
+	"image"
+
+	"github.com/cod3rboy/yaps/utils"
+	"github.com/fogleman/gg"
+	"golang.org/x/image/draw"
+)
+
+// A PreviewParams stores parameters for generating an image from a decoded preview e.g. a BlurHash.
+type PreviewParams struct {
+	Format  string      // Image extension
+	*Size               // Image Size
+	Preview image.Image // Decoded preview which is stretched to Size
+	Scale   float64     // Value by which to scale Size
+}
+
+// GeneratePreview generates an image by resizing the preview to the image dimensions with bilinear interpolation.
+//
+// Transparent areas of the preview are filled with white color for formats which do not support transparency.
+//
+// It returns [ImageResult], nil when image is generated successfully.
+// If error occurs while generating image, it returns nil, error.
+func GeneratePreview(params *PreviewParams) (*ImageResult, error) {
+	w := utils.ScaleDimension(params.Width, params.Scale)
+	h := utils.ScaleDimension(params.Height, params.Scale)
+
+	canvas := gg.NewContext(w, h)
+	if !SupportsTransparency(params.Format) {
+		FillBackground(canvas, &matteColor)
+	}
+	resized := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.BiLinear.Scale(resized, resized.Bounds(), params.Preview, params.Preview.Bounds(), draw.Src, nil)
+	canvas.DrawImage(resized, 0, 0)
+
+	return encodeResult(canvas, params.Format)
+}
@@ -0,0 +1,186 @@
+package img
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/fogleman/gg"
+	otfont "github.com/go-text/typesetting/font"
+	"github.com/go-text/typesetting/segmenter"
+)
+
+// Fraction of the bounds which rotated and vertical text may fill
+const textFill = 0.9
+
+// Maximum number of lines or columns of rotated and vertical text
+const maxTextLines = 10
+
+// Initial font size of diagonal text as a fraction of the extent across the diagonal
+const diagonalFontSize = 0.5
+
+// Advance between columns of vertical text as a multiple of the font height
+const columnSpacing = 1.2
+
+// A TextLayout stores how text is oriented in its bounds.
+type TextLayout struct {
+	Rotation float64 // Clockwise rotation of text in degrees
+	Diagonal bool    // Whether text runs from the bottom left corner to the top right corner, overrides Rotation
+	Vertical bool    // Whether text is written top to bottom in columns from right to left, ignores Rotation
+}
+
+// IsHorizontal returns true if the layout writes unrotated horizontal text.
+func (l TextLayout) IsHorizontal() bool {
+	return !l.Diagonal && !l.Vertical && math.Mod(l.Rotation, 360) == 0
+}
+
+// DrawTextLayout draws the given text centered in the bounds with given color, font and layout.
+//
+// The text is wrapped and its font size shrinks until the rotated text block fits in the bounds.
+// The initial font size is calculated like [DrawText] from the extent of the bounds across the text direction,
+// and diagonal text starts larger so that it runs from corner to corner.
+func DrawTextLayout(canvas *gg.Context, text string, color *Color, font *otfont.Font, bounds Rect, layout TextLayout) {
+	if layout.Vertical {
+		drawVerticalText(canvas, text, color, font, bounds)
+		return
+	}
+	if layout.Diagonal {
+		// Rising diagonal goes up while y axis goes down. Diagonal labels start larger to run corner to corner.
+		drawRotatedText(canvas, text, color, font, bounds, -math.Atan2(bounds.Height, bounds.Width), diagonalFontSize, 1)
+		return
+	}
+	drawRotatedText(canvas, text, color, font, bounds, layout.Rotation*math.Pi/180, 0.2, maxTextLines)
+}
+
+// drawRotatedText draws the text with given color rotated by angle in radians about the centre of bounds.
+//
+// The initial font size is the extent of bounds across the text direction * [PX_TO_PT] * sizeFactor.
+// The text prefers at most preferredLines lines until the font size shrinks to a quarter of the initial size.
+func drawRotatedText(canvas *gg.Context, text string, color *Color, font *otfont.Font, bounds Rect, angle, sizeFactor float64, preferredLines int) {
+	cos, sin := math.Abs(math.Cos(angle)), math.Abs(math.Sin(angle))
+	// Extent of the bounds across the text direction through the centre
+	across := math.Min(bounds.Height/math.Max(cos, 1e-9), bounds.Width/math.Max(sin, 1e-9))
+
+	shaper := newTextShaper(font)
+	initialSize := across * PX_TO_PT * sizeFactor
+	var lines []textLine
+	for fontSize := initialSize; fontSize > 1; fontSize *= 0.9 {
+		shaper.setSize(fontSize)
+		maxLines := maxTextLines
+		if fontSize > initialSize/4 {
+			maxLines = preferredLines
+		}
+		if lines = fitRotatedBlock(shaper, text, bounds, cos, sin, maxLines); lines != nil {
+			break
+		}
+	}
+	if lines == nil {
+		lines = shaper.wrap(text, bounds.Width)
+	}
+
+	cx, cy := bounds.X+bounds.Width/2, bounds.Y+bounds.Height/2
+	canvas.Push()
+	canvas.RotateAbout(angle, cx, cy)
+	shaper.drawLines(canvas, lines, color, cx, cy, shapeLineSpacing)
+	canvas.Pop()
+}
+
+// fitRotatedBlock returns the lines of the text wrapped by the shaper which fit in the bounds in at most
+// maxLines lines when rotated by the angle with given absolute cosine and sine.
+// If the text does not fit, it returns nil.
+func fitRotatedBlock(shaper *textShaper, text string, bounds Rect, cos, sin float64, maxLines int) []textLine {
+	availableW, availableH := bounds.Width*textFill, bounds.Height*textFill
+	lineHeight := shaper.height * shapeLineSpacing
+	for lines := 1; lines <= maxLines; lines++ {
+		// A block of width a and height b rotated by the angle takes a*cos + b*sin by a*sin + b*cos
+		blockH := float64(lines) * lineHeight
+		blockW := math.Inf(1)
+		if cos > 1e-9 {
+			blockW = math.Min(blockW, (availableW-blockH*sin)/cos)
+		}
+		if sin > 1e-9 {
+			blockW = math.Min(blockW, (availableH-blockH*cos)/sin)
+		}
+		if blockW <= 0 {
+			return nil
+		}
+		wrapped := shaper.wrap(text, blockW)
+		if len(wrapped) > lines {
+			continue
+		}
+		for _, line := range wrapped {
+			if line.width > blockW {
+				return nil
+			}
+		}
+		return wrapped
+	}
+	return nil
+}
+
+// drawVerticalText draws the text with given color top to bottom in columns from right to left, centered in bounds.
+//
+// Each character, i.e. grapheme cluster, is drawn upright in a square cell. A line break starts a new column,
+// and columns wrap when they overflow the bounds height.
+func drawVerticalText(canvas *gg.Context, text string, color *Color, font *otfont.Font, bounds Rect) {
+	shaper := newTextShaper(font)
+	var columns [][]string
+	for fontSize := bounds.Width * PX_TO_PT * 0.2; fontSize > 1; fontSize *= 0.9 {
+		shaper.setSize(fontSize)
+		cell := shaper.height * shapeLineSpacing
+		perColumn := int(bounds.Height * textFill / cell)
+		if perColumn < 1 {
+			continue
+		}
+		columns = verticalColumns(text, perColumn)
+		if len(columns) <= maxTextLines && float64(len(columns))*shaper.height*columnSpacing <= bounds.Width*textFill {
+			break
+		}
+	}
+
+	cell := shaper.height * shapeLineSpacing
+	advance := shaper.height * columnSpacing
+	// First column is at the right
+	x := bounds.X + bounds.Width/2 + advance*float64(len(columns)-1)/2
+	// Columns are aligned at the top of the longest column
+	longest := 0
+	for _, column := range columns {
+		if len(column) > longest {
+			longest = len(column)
+		}
+	}
+	top := bounds.Y + bounds.Height/2 - cell*float64(longest-1)/2
+	for _, column := range columns {
+		y := top
+		for _, char := range column {
+			shaper.drawLines(canvas, shaper.wrap(char, math.Inf(1)), color, x, y, 1)
+			y += cell
+		}
+		x -= advance
+	}
+}
+
+// verticalColumns splits the text into columns of at most perColumn characters, where a character is a grapheme
+// cluster e.g. a letter with combining marks or an emoji sequence, so that it is shaped as a whole.
+// Line breaks start a new column and spaces at the start of a column are dropped.
+func verticalColumns(text string, perColumn int) [][]string {
+	var graphemes segmenter.Segmenter
+	columns := [][]string{}
+	for _, line := range strings.Split(text, "\n") {
+		column := []string{}
+		graphemes.Init([]rune(strings.TrimSpace(line)))
+		for iterator := graphemes.GraphemeIterator(); iterator.Next(); {
+			char := iterator.Grapheme().Text
+			if len(column) == perColumn {
+				columns = append(columns, column)
+				column = []string{}
+			}
+			if len(column) == 0 && len(char) == 1 && unicode.IsSpace(char[0]) {
+				continue
+			}
+			column = append(column, string(char))
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
@@ -0,0 +1,109 @@
+package img
+
+import (
+	"image"
+	"reflect"
+	"testing"
+
+	"github.com/fogleman/gg"
+)
+
+// inkBounds returns the bounding box of the pixels darker than mid gray on the canvas.
+func inkBounds(canvas *gg.Context) image.Rectangle {
+	ink := image.Rectangle{}
+	for y := 0; y < canvas.Height(); y++ {
+		for x := 0; x < canvas.Width(); x++ {
+			if r, _, _, _ := canvas.Image().At(x, y).RGBA(); r>>8 < 0x80 {
+				ink = ink.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return ink
+}
+
+func TestDrawTextLayout(t *testing.T) {
+	font, err := getFont(DefaultFont)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		text   string
+		layout TextLayout
+		check  func(ink image.Rectangle) bool
+	}{
+		{
+			name:   "Quarter turn runs vertically",
+			text:   "Rotated label",
+			layout: TextLayout{Rotation: 90},
+			check:  func(ink image.Rectangle) bool { return ink.Dy() > ink.Dx() },
+		},
+		{
+			name:   "Diagonal spans both directions",
+			text:   "Wireframe",
+			layout: TextLayout{Diagonal: true},
+			check:  func(ink image.Rectangle) bool { return ink.Dx() > 150 && ink.Dy() > 50 },
+		},
+		{
+			name:   "Vertical writing stacks characters",
+			text:   "縦書き",
+			layout: TextLayout{Vertical: true},
+			check:  func(ink image.Rectangle) bool { return ink.Dy() > ink.Dx() },
+		},
+		{
+			name:   "Long rotated text stays inside",
+			text:   "The quick brown fox jumps over the lazy dog again and again and again",
+			layout: TextLayout{Rotation: 30},
+			check:  func(ink image.Rectangle) bool { return !ink.Empty() },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canvas := gg.NewContext(300, 150)
+			FillBackground(canvas, &White)
+			DrawTextLayout(canvas, tt.text, &Black, font, Rect{Width: 300, Height: 150}, tt.layout)
+			ink := inkBounds(canvas)
+			if ink.Empty() {
+				t.Fatal("expected text to be drawn")
+			}
+			if ink.Min.X < 1 || ink.Min.Y < 1 || ink.Max.X > 299 || ink.Max.Y > 149 {
+				t.Errorf("text drawn outside canvas, ink bounds = %v", ink)
+			}
+			if !tt.check(ink) {
+				t.Errorf("unexpected ink bounds = %v", ink)
+			}
+		})
+	}
+}
+
+func TestTextLayoutIsHorizontal(t *testing.T) {
+	tests := []struct {
+		layout TextLayout
+		want   bool
+	}{
+		{layout: TextLayout{}, want: true},
+		{layout: TextLayout{Rotation: 360}, want: true},
+		{layout: TextLayout{Rotation: 45}, want: false},
+		{layout: TextLayout{Diagonal: true}, want: false},
+		{layout: TextLayout{Vertical: true}, want: false},
+	}
+	for _, tt := range tests {
+		if got := tt.layout.IsHorizontal(); got != tt.want {
+			t.Errorf("%+v.IsHorizontal() = %v, want %v", tt.layout, got, tt.want)
+		}
+	}
+}
+
+func TestVerticalColumns(t *testing.T) {
+	got := verticalColumns("東京タワー\n 夜", 3)
+	want := [][]string{{"東", "京", "タ"}, {"ワ", "ー"}, {"夜"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("verticalColumns() = %q, want %q", got, want)
+	}
+	// Combining marks and emoji sequences stay with their base character
+	got = verticalColumns("Cafe\u0301 👍🏽", 4)
+	want = [][]string{{"C", "a", "f", "e\u0301"}, {"👍🏽"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("verticalColumns() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+package img
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/fogleman/gg"
+	otfont "github.com/go-text/typesetting/font"
+)
+
+// Constants for image shapes, in addition to SHAPE_CIRCLE
+const (
+	SHAPE_ELLIPSE  = "ellipse"
+	SHAPE_HEXAGON  = "hexagon"
+	SHAPE_SQUIRCLE = "squircle"
+)
+
+// Exponent of the superellipse |x|^n + |y|^n = 1 which draws a squircle
+const squircleExponent = 4
+
+// Number of points of the polygon which approximates a squircle
+const squirclePoints = 128
+
+// Line spacing of text drawn inside a shape
+const shapeLineSpacing = 1.2
+
+// A Rect represents a rectangle by its top left corner, width and height.
+type Rect struct {
+	X, Y, Width, Height float64
+}
+
+// DrawShape adds the path of the shape, centered in the canvas, to the canvas.
+//
+// SHAPE_ELLIPSE fills the canvas, while SHAPE_CIRCLE, SHAPE_HEXAGON and SHAPE_SQUIRCLE are regular shapes sized to the
+// smaller dimension of the canvas. The hexagon has a pointy top.
+// If the shape is unknown, it returns an error.
+func DrawShape(canvas *gg.Context, shape string) error {
+	return drawShapeIn(canvas, shape, Rect{Width: float64(canvas.Width()), Height: float64(canvas.Height())})
+}
+
+// drawShapeIn adds the path of the shape, centered in bounds, to the canvas like [DrawShape] does for the whole canvas.
+func drawShapeIn(canvas *gg.Context, shape string, bounds Rect) error {
+	w, h := bounds.Width, bounds.Height
+	cx, cy := bounds.X+w/2, bounds.Y+h/2
+	side := math.Min(w, h)
+	switch shape {
+	case SHAPE_CIRCLE:
+		canvas.DrawCircle(cx, cy, side/2)
+	case SHAPE_ELLIPSE:
+		canvas.DrawEllipse(cx, cy, w/2, h/2)
+	case SHAPE_HEXAGON:
+		// Pointy top regular polygon is rotated so that a vertex is at the top
+		canvas.DrawRegularPolygon(6, cx, cy, side/2, math.Pi/6)
+	case SHAPE_SQUIRCLE:
+		for i := 0; i < squirclePoints; i++ {
+			angle := 2 * math.Pi * float64(i) / squirclePoints
+			cos, sin := math.Cos(angle), math.Sin(angle)
+			x := cx + side/2*math.Copysign(math.Pow(math.Abs(cos), 2.0/squircleExponent), cos)
+			y := cy + side/2*math.Copysign(math.Pow(math.Abs(sin), 2.0/squircleExponent), sin)
+			canvas.LineTo(x, y)
+		}
+		canvas.ClosePath()
+	default:
+		return fmt.Errorf("unknown shape %s", shape)
+	}
+	return nil
+}
+
+// ShapeBounds returns the largest rectangle inscribed in the shape drawn by [DrawShape] on a width x height canvas.
+//
+// If the shape is unknown, it returns an empty rectangle, error.
+func ShapeBounds(shape string, width, height int) (Rect, error) {
+	w, h := float64(width), float64(height)
+	side := math.Min(w, h)
+	var boundsW, boundsH float64
+	switch shape {
+	case SHAPE_CIRCLE:
+		boundsW, boundsH = side/math.Sqrt2, side/math.Sqrt2
+	case SHAPE_ELLIPSE:
+		boundsW, boundsH = w/math.Sqrt2, h/math.Sqrt2
+	case SHAPE_HEXAGON:
+		// Full width between the vertical edges and the height of those edges
+		boundsW, boundsH = side/2*math.Sqrt(3), side/2
+	case SHAPE_SQUIRCLE:
+		// Corners where |x| = |y| on the superellipse
+		boundsW = side * math.Pow(2, -1.0/squircleExponent)
+		boundsH = boundsW
+	default:
+		return Rect{}, fmt.Errorf("unknown shape %s", shape)
+	}
+	return Rect{X: (w - boundsW) / 2, Y: (h - boundsH) / 2, Width: boundsW, Height: boundsH}, nil
+}
+
+// DrawTextInRect draws the given text centered in the rectangle with given color and font.
+//
+// The font size is calculated by rectangle height * [PX_TO_PT] * 0.2 like [DrawText].
+// The overflow of the text is handled like [DrawTextWithOverflow] in the rectangle, where the default policy is
+// [OVERFLOW_SHRINK] i.e. the font size shrinks until the wrapped text fits in the rectangle.
+func DrawTextInRect(canvas *gg.Context, text string, color *Color, font *otfont.Font, bounds Rect, overflow TextOverflow) {
+	if overflow.Policy == "" {
+		overflow.Policy = OVERFLOW_SHRINK
+	}
+	drawTextBox(canvas, text, color, font, bounds.Height*PX_TO_PT*0.2, bounds, shapeLineSpacing, overflow)
+}
@@ -0,0 +1,95 @@
+package img
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fogleman/gg"
+)
+
+func TestShapeBounds(t *testing.T) {
+	tests := []struct {
+		shape string
+		want  Rect
+	}{
+		{shape: SHAPE_CIRCLE, want: Rect{X: 129.29, Y: 29.29, Width: 141.42, Height: 141.42}},
+		{shape: SHAPE_ELLIPSE, want: Rect{X: 58.58, Y: 29.29, Width: 282.84, Height: 141.42}},
+		{shape: SHAPE_HEXAGON, want: Rect{X: 113.40, Y: 50, Width: 173.21, Height: 100}},
+		{shape: SHAPE_SQUIRCLE, want: Rect{X: 115.91, Y: 15.91, Width: 168.18, Height: 168.18}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shape, func(t *testing.T) {
+			got, err := ShapeBounds(tt.shape, 400, 200)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got.X-tt.want.X) > 0.01 || math.Abs(got.Y-tt.want.Y) > 0.01 || math.Abs(got.Width-tt.want.Width) > 0.01 || math.Abs(got.Height-tt.want.Height) > 0.01 {
+				t.Errorf("ShapeBounds() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+	if _, err := ShapeBounds("star", 100, 100); err == nil {
+		t.Error("expected error for unknown shape")
+	}
+}
+
+func TestDrawTextInRect(t *testing.T) {
+	canvas := gg.NewContext(200, 200)
+	FillBackground(canvas, &White)
+	font, err := getFont(DefaultFont)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bounds := Rect{X: 50, Y: 50, Width: 100, Height: 100}
+	DrawTextInRect(canvas, "The quick brown fox jumps over the lazy dog again and again", &Black, font, bounds, TextOverflow{})
+
+	inked := false
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 200; x++ {
+			if r, _, _, _ := canvas.Image().At(x, y).RGBA(); r>>8 < 0x80 {
+				inked = true
+				if float64(x) < bounds.X-1 || float64(x) > bounds.X+bounds.Width+1 || float64(y) < bounds.Y-1 || float64(y) > bounds.Y+bounds.Height+1 {
+					t.Fatalf("text drawn outside bounds at (%d, %d)", x, y)
+				}
+			}
+		}
+	}
+	if !inked {
+		t.Error("expected text to be drawn")
+	}
+}
+
+func TestRenderShape(t *testing.T) {
+	params := &ImageParams{
+		Format:          IMAGE_PNG,
+		Size:            &Size{Width: 100, Height: 100},
+		BackgroundColor: &Color{R: 0x33, G: 0x66, B: 0x99},
+		TextColor:       &White,
+		Scale:           1,
+		Text:            "100 x 100",
+		Shape:           SHAPE_HEXAGON,
+	}
+	rendered, err := Render(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, a := rendered.At(2, 2).RGBA(); a != 0 {
+		t.Errorf("expected transparent corner, actual alpha = %d", a>>8)
+	}
+	if r, _, _, _ := rendered.At(50, 5).RGBA(); r>>8 != 0x33 {
+		t.Errorf("expected background color at top vertex, actual red = %d", r>>8)
+	}
+
+	params.Matte = &Color{R: 0xFF}
+	if rendered, err = Render(params); err != nil {
+		t.Fatal(err)
+	}
+	if r, g, _, a := rendered.At(2, 2).RGBA(); r>>8 != 0xFF || g != 0 || a>>8 != 0xFF {
+		t.Errorf("expected matte color at corner, actual color = %d, %d, alpha %d", r>>8, g>>8, a>>8)
+	}
+
+	params.Shape = "star"
+	if _, err := Render(params); err == nil {
+		t.Error("expected error for unknown shape")
+	}
+}
@@ -0,0 +1,454 @@
+package img
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cod3rboy/yaps/utils"
+	"github.com/cod3rboy/yaps/utils/stringutils"
+	"github.com/fogleman/gg"
+	"golang.org/x/image/draw"
+	"gopkg.in/yaml.v3"
+)
+
+// Constants for template layer types
+const (
+	LAYER_FILL     = "fill"
+	LAYER_GRADIENT = "gradient"
+	LAYER_SHAPE    = "shape"
+	LAYER_TEXT     = "text"
+	LAYER_IMAGE    = "image"
+	LAYER_OVERLAY  = "overlay"
+)
+
+// Extensions of the template files loaded by [LoadTemplates]
+const (
+	templateExtensionJSON = ".json"
+	templateExtensionYAML = ".yaml"
+	templateExtensionYML  = ".yml"
+)
+
+// Pattern of the name of a template variable
+var templateVariablePattern = regexp.MustCompile(`^[a-z]+$`)
+
+// Mapping of a horizontal text alignment to its anchor, as a fraction of the free space in the layer box
+var templateAligns = map[string]float64{"": 0, "left": 0, "center": 0.5, "right": 1}
+
+// Mapping of a vertical text alignment to its anchor, as a fraction of the free space in the layer box
+var templateVAligns = map[string]float64{"": 0, "top": 0, "center": 0.5, "bottom": 1}
+
+// ErrTemplateValue is returned by [GenerateTemplate] when a value of a layer is invalid once its variables are
+// replaced e.g. a color variable which is not a hexadecimal color.
+var ErrTemplateValue = errors.New("invalid template value")
+
+// Mapping of a template name to its template.
+var templates = map[string]*Template{}
+
+// A Template describes an image composed of layers drawn from bottom to top.
+//
+// The string values of layers can contain variable slots e.g. {title}, which are replaced when the image is
+// generated, see [TemplateParams].
+type Template struct {
+	Width     int               `json:"width" yaml:"width"`         // Image width in pixels
+	Height    int               `json:"height" yaml:"height"`       // Image height in pixels
+	Variables map[string]string `json:"variables" yaml:"variables"` // Default values of the variables by name
+	Layers    []TemplateLayer   `json:"layers" yaml:"layers"`       // Layers in drawing order
+}
+
+// A TemplateLayer stores parameters of one layer of a [Template]. Each layer type uses a subset of the fields.
+// Lengths are in pixels of the template before scaling and colors are hexadecimal e.g. ff0000.
+type TemplateLayer struct {
+	Type     string   `json:"type" yaml:"type"`         // Layer type, one of the LAYER constants
+	X        float64  `json:"x" yaml:"x"`               // Left edge of the layer box
+	Y        float64  `json:"y" yaml:"y"`               // Top edge of the layer box
+	Width    float64  `json:"width" yaml:"width"`       // Width of the layer box, 0 to extend it to the right edge
+	Height   float64  `json:"height" yaml:"height"`     // Height of the layer box, 0 to extend it to the bottom edge
+	Opacity  *float64 `json:"opacity" yaml:"opacity"`   // Opacity of the layer, in range [0, 1], nil for opaque
+	Radius   float64  `json:"radius" yaml:"radius"`     // Corner radius of fill, gradient and image layers
+	Color    string   `json:"color" yaml:"color"`       // Color of fill, shape and text layers, black by default
+	From     string   `json:"from" yaml:"from"`         // Start color of gradient layers
+	To       string   `json:"to" yaml:"to"`             // End color of gradient layers
+	Angle    float64  `json:"angle" yaml:"angle"`       // Direction of gradient layers in degrees, 0 upwards and 90 rightwards
+	Shape    string   `json:"shape" yaml:"shape"`       // Shape of shape layers e.g. SHAPE_CIRCLE
+	Text     string   `json:"text" yaml:"text"`         // Text of text layers, wrapped at the box width
+	Font     string   `json:"font" yaml:"font"`         // Font of text layers, DefaultFont by default
+	Size     float64  `json:"size" yaml:"size"`         // Font size of text layers
+	Align    string   `json:"align" yaml:"align"`       // Horizontal alignment of text and overlay layers: left, center or right
+	VAlign   string   `json:"valign" yaml:"valign"`     // Vertical alignment of text and overlay layers: top, center or bottom
+	Spacing  float64  `json:"spacing" yaml:"spacing"`   // Line spacing of text layers, 1.2 by default
+	MaxLines int      `json:"maxlines" yaml:"maxlines"` // Maximum number of lines of text layers, 0 for no limit
+	Overflow string   `json:"overflow" yaml:"overflow"` // Overflow policy of text layers, OVERFLOW_ELLIPSIS by default
+	MinSize  float64  `json:"minsize" yaml:"minsize"`   // Smallest font size of text layers shrunk by OVERFLOW_SHRINK, 0 for no limit
+	Tag      string   `json:"tag" yaml:"tag"`           // Photo tag of image layers, see [LoadPhotos]
+	Index    int      `json:"index" yaml:"index"`       // Index of the photo of image layers among the photos with the tag, wraps around
+	Crop     string   `json:"crop" yaml:"crop"`         // Crop strategy of image layers, CROP_CENTER by default
+	Asset    string   `json:"asset" yaml:"asset"`       // Asset of overlay layers, fitted in the box, see [LoadAssets]
+}
+
+// A TemplateParams stores parameters for template image generation.
+type TemplateParams struct {
+	Format   string              // Image extension
+	Template *Template           // Template of the image
+	Scale    float64             // Value by which to scale the template size and lengths
+	Expand   func(string) string // Replaces the variable slots in the string values of the layers, nil to keep them
+}
+
+// LoadTemplates registers all the JSON (.json) and YAML (.yaml or .yml) template files present in directory dir.
+//
+// Each template is registered with the file name without extension e.g. card.yaml is registered as card.
+// If an error occurs while reading, parsing or validating a template file, it returns that error.
+func LoadTemplates(dir string) error {
+	for _, extension := range []string{templateExtensionJSON, templateExtensionYAML, templateExtensionYML} {
+		paths, err := filepath.Glob(filepath.Join(dir, "*"+extension))
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			loaded, err := ParseTemplate(data, extension)
+			if err != nil {
+				return fmt.Errorf("failed to parse template %s: %w", path, err)
+			}
+			templates[strings.TrimSuffix(filepath.Base(path), extension)] = loaded
+		}
+	}
+	return nil
+}
+
+// ParseTemplate parses and validates the template data of a file with given extension.
+//
+// Unknown fields are rejected so that misspelt properties do not go unnoticed.
+func ParseTemplate(data []byte, extension string) (*Template, error) {
+	parsed := &Template{}
+	if extension == templateExtensionJSON {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(parsed); err != nil {
+			return nil, err
+		}
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(parsed); err != nil {
+			return nil, err
+		}
+	}
+	if err := parsed.validate(); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// FindTemplate returns the template registered with given name, or nil if there is none.
+func FindTemplate(name string) *Template {
+	return templates[name]
+}
+
+// validate returns an error if a value of the template which cannot contain variables is invalid.
+func (t *Template) validate() error {
+	if t.Width <= 0 || t.Height <= 0 {
+		return errors.New("template width and height must be positive")
+	}
+	for name := range t.Variables {
+		if !templateVariablePattern.MatchString(name) {
+			return fmt.Errorf("template variable name %q must be lowercase letters", name)
+		}
+	}
+	for i, layer := range t.Layers {
+		if err := layer.validate(); err != nil {
+			return fmt.Errorf("layer %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// validate returns an error if a value of the layer which cannot contain variables is invalid.
+func (l *TemplateLayer) validate() error {
+	if l.Width < 0 || l.Height < 0 || l.Radius < 0 {
+		return errors.New("width, height and radius must not be negative")
+	}
+	if l.Opacity != nil && (*l.Opacity < 0 || *l.Opacity > 1) {
+		return errors.New("opacity must be in range [0, 1]")
+	}
+	switch l.Type {
+	case LAYER_FILL:
+	case LAYER_GRADIENT:
+		if l.From == "" || l.To == "" {
+			return errors.New("gradient must have from and to colors")
+		}
+	case LAYER_SHAPE:
+		if _, err := ShapeBounds(l.Shape, 1, 1); err != nil {
+			return err
+		}
+	case LAYER_TEXT:
+		if l.Size <= 0 {
+			return errors.New("text size must be positive")
+		}
+		if _, exists := templateAligns[l.Align]; !exists {
+			return fmt.Errorf("unknown text align %s", l.Align)
+		}
+		if _, exists := templateVAligns[l.VAlign]; !exists {
+			return fmt.Errorf("unknown text valign %s", l.VAlign)
+		}
+		if l.Spacing < 0 || l.MaxLines < 0 || l.MinSize < 0 {
+			return errors.New("text spacing, maxlines and minsize must not be negative")
+		}
+		switch l.Overflow {
+		case "", OVERFLOW_VISIBLE, OVERFLOW_CLIP, OVERFLOW_ELLIPSIS, OVERFLOW_SHRINK:
+		default:
+			return fmt.Errorf("unknown text overflow %s", l.Overflow)
+		}
+	case LAYER_IMAGE:
+		switch l.Crop {
+		case "", CROP_CENTER, CROP_ENTROPY, CROP_ATTENTION:
+		default:
+			return fmt.Errorf("unknown crop strategy %s", l.Crop)
+		}
+	case LAYER_OVERLAY:
+		if l.Asset == "" {
+			return errors.New("overlay must have an asset")
+		}
+		if _, exists := templateAligns[l.Align]; !exists {
+			return fmt.Errorf("unknown overlay align %s", l.Align)
+		}
+		if _, exists := templateVAligns[l.VAlign]; !exists {
+			return fmt.Errorf("unknown overlay valign %s", l.VAlign)
+		}
+	default:
+		return fmt.Errorf("unknown layer type %s", l.Type)
+	}
+	return nil
+}
+
+// expanded returns a copy of the layer with the variable slots of its string values replaced by expand.
+func (l TemplateLayer) expanded(expand func(string) string) TemplateLayer {
+	if expand == nil {
+		return l
+	}
+	for _, value := range []*string{&l.Color, &l.From, &l.To, &l.Text, &l.Font, &l.Tag, &l.Asset} {
+		*value = expand(*value)
+	}
+	return l
+}
+
+// GenerateTemplate generates an image by drawing the layers of the template, scaled by the scale factor,
+// on a transparent canvas.
+//
+// It returns [ImageResult], nil when image is generated successfully.
+// If a color, font, photo tag or asset of a layer is invalid after replacing its variables,
+// it returns nil, error wrapping [ErrTemplateValue]. If another error occurs, it returns nil, error.
+func GenerateTemplate(params *TemplateParams) (*ImageResult, error) {
+	template := params.Template
+	canvas := gg.NewContext(utils.ScaleDimension(template.Width, params.Scale), utils.ScaleDimension(template.Height, params.Scale))
+	for i, layer := range template.Layers {
+		if err := drawTemplateLayer(canvas, layer.expanded(params.Expand), params.Scale); err != nil {
+			return nil, fmt.Errorf("layer %d: %w", i+1, err)
+		}
+	}
+	return encodeResult(canvas, params.Format)
+}
+
+// drawTemplateLayer draws the layer on the canvas with its lengths scaled by scale.
+//
+// A translucent layer is drawn on a transparent layer of the canvas size which is then blended with the canvas.
+func drawTemplateLayer(canvas *gg.Context, layer TemplateLayer, scale float64) error {
+	if layer.Opacity == nil || *layer.Opacity == 1 {
+		return drawTemplateLayerContent(canvas, layer, scale)
+	}
+	translucent := gg.NewContext(canvas.Width(), canvas.Height())
+	if err := drawTemplateLayerContent(translucent, layer, scale); err != nil {
+		return err
+	}
+	dst, ok := canvas.Image().(draw.Image)
+	if !ok {
+		return errors.New("canvas image is not drawable")
+	}
+	mask := image.NewUniform(color.Alpha{A: uint8(math.Round(*layer.Opacity * 0xFF))})
+	draw.DrawMask(dst, dst.Bounds(), translucent.Image(), image.Point{}, mask, image.Point{}, draw.Over)
+	return nil
+}
+
+// drawTemplateLayerContent draws the content of the layer in its box on the canvas.
+func drawTemplateLayerContent(canvas *gg.Context, layer TemplateLayer, scale float64) error {
+	box := Rect{X: layer.X * scale, Y: layer.Y * scale, Width: layer.Width * scale, Height: layer.Height * scale}
+	if box.Width == 0 {
+		box.Width = float64(canvas.Width()) - box.X
+	}
+	if box.Height == 0 {
+		box.Height = float64(canvas.Height()) - box.Y
+	}
+	radius := layer.Radius * scale
+
+	switch layer.Type {
+	case LAYER_FILL:
+		fill, err := parseTemplateColor(layer.Color)
+		if err != nil {
+			return err
+		}
+		canvas.DrawRoundedRectangle(box.X, box.Y, box.Width, box.Height, radius)
+		canvas.SetRGBA255(int(fill.R), int(fill.G), int(fill.B), 0xFF)
+		canvas.Fill()
+	case LAYER_GRADIENT:
+		from, err := parseTemplateColor(layer.From)
+		if err != nil {
+			return err
+		}
+		to, err := parseTemplateColor(layer.To)
+		if err != nil {
+			return err
+		}
+		// Gradient line through the box centre, long enough for the corners to get the end colors like CSS
+		angle := layer.Angle * math.Pi / 180
+		dx, dy := math.Sin(angle), -math.Cos(angle)
+		half := (math.Abs(box.Width*dx) + math.Abs(box.Height*dy)) / 2
+		cx, cy := box.X+box.Width/2, box.Y+box.Height/2
+		gradient := gg.NewLinearGradient(cx-dx*half, cy-dy*half, cx+dx*half, cy+dy*half)
+		gradient.AddColorStop(0, color.RGBA{R: from.R, G: from.G, B: from.B, A: 0xFF})
+		gradient.AddColorStop(1, color.RGBA{R: to.R, G: to.G, B: to.B, A: 0xFF})
+		canvas.DrawRoundedRectangle(box.X, box.Y, box.Width, box.Height, radius)
+		canvas.SetFillStyle(gradient)
+		canvas.Fill()
+	case LAYER_SHAPE:
+		fill, err := parseTemplateColor(layer.Color)
+		if err != nil {
+			return err
+		}
+		if err := drawShapeIn(canvas, layer.Shape, box); err != nil {
+			return err
+		}
+		canvas.SetRGBA255(int(fill.R), int(fill.G), int(fill.B), 0xFF)
+		canvas.Fill()
+	case LAYER_TEXT:
+		return drawTemplateText(canvas, layer, box, scale)
+	case LAYER_IMAGE:
+		return drawTemplatePhoto(canvas, layer, box, radius)
+	case LAYER_OVERLAY:
+		return drawTemplateAsset(canvas, layer, box)
+	}
+	return nil
+}
+
+// drawTemplateText draws the text of the layer aligned in the box, wrapped at the box width.
+func drawTemplateText(canvas *gg.Context, layer TemplateLayer, box Rect, scale float64) error {
+	textColor, err := parseTemplateColor(layer.Color)
+	if err != nil {
+		return err
+	}
+	fontName := layer.Font
+	if fontName == "" {
+		fontName = DefaultFont
+	}
+	font, err := getFont(fontName)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrTemplateValue, err)
+	}
+	spacing := layer.Spacing
+	if spacing == 0 {
+		spacing = shapeLineSpacing
+	}
+	overflow := TextOverflow{MaxLines: layer.MaxLines, Policy: layer.Overflow, MinSize: layer.MinSize * scale * PX_TO_PT}
+	if overflow.Policy == "" {
+		overflow.Policy = OVERFLOW_ELLIPSIS
+	}
+
+	shaper := newTextShaper(font)
+	lines := fitText(shaper, layer.Text, layer.Size*scale*PX_TO_PT, box, spacing, overflow)
+	if overflow.Policy == OVERFLOW_CLIP {
+		canvas.Push()
+		defer canvas.Pop()
+		canvas.DrawRectangle(box.X, box.Y, box.Width, box.Height)
+		canvas.Clip()
+	}
+	align, valign := templateAligns[layer.Align], templateVAligns[layer.VAlign]
+	baseline := box.Y + (box.Height-shaper.blockHeight(len(lines), spacing))*valign + shaper.height
+	for _, line := range lines {
+		drawLine(canvas, line, textColor, box.X+(box.Width-line.width)*align, baseline)
+		baseline += shaper.height * spacing
+	}
+	return nil
+}
+
+// drawTemplatePhoto draws the photo of the layer cropped to cover the box, with rounded corners of given radius.
+func drawTemplatePhoto(canvas *gg.Context, layer TemplateLayer, box Rect, radius float64) error {
+	count := PhotoCount(layer.Tag)
+	if count == 0 {
+		return fmt.Errorf("%w: no photos found for tag %q", ErrTemplateValue, layer.Tag)
+	}
+	// Index wraps around like the index of the photo route
+	photo, err := OpenPhoto(layer.Tag, (layer.Index%count+count)%count)
+	if err != nil {
+		return err
+	}
+	crop := layer.Crop
+	if crop == "" {
+		crop = CROP_CENTER
+	}
+	w, h := int(math.Max(1, math.Round(box.Width))), int(math.Max(1, math.Round(box.Height)))
+	cropRect, err := CropRect(photo, w, h, crop)
+	if err != nil {
+		return err
+	}
+	resized := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(resized, resized.Bounds(), photo, cropRect, draw.Src, nil)
+
+	canvas.Push()
+	defer canvas.Pop()
+	canvas.DrawRoundedRectangle(box.X, box.Y, box.Width, box.Height, radius)
+	canvas.Clip()
+	canvas.DrawImage(resized, int(math.Round(box.X)), int(math.Round(box.Y)))
+	return nil
+}
+
+// drawTemplateAsset draws the asset of the layer at the largest size which fits in the box, aligned in the box.
+//
+// Unlike text, the asset is centered in the box by default.
+func drawTemplateAsset(canvas *gg.Context, layer TemplateLayer, box Rect) error {
+	loaded, exists := assets[layer.Asset]
+	if !exists {
+		return fmt.Errorf("%w: asset not found with name %s", ErrTemplateValue, layer.Asset)
+	}
+	w, h := box.Width, box.Width/loaded.ratio
+	if h > box.Height {
+		w, h = box.Height*loaded.ratio, box.Height
+	}
+	rendered, err := loaded.render(int(math.Max(1, math.Round(w))), int(math.Max(1, math.Round(h))))
+	if err != nil {
+		return err
+	}
+	align, valign := 0.5, 0.5
+	if layer.Align != "" {
+		align = templateAligns[layer.Align]
+	}
+	if layer.VAlign != "" {
+		valign = templateVAligns[layer.VAlign]
+	}
+	canvas.DrawImage(rendered, int(math.Round(box.X+(box.Width-w)*align)), int(math.Round(box.Y+(box.Height-h)*valign)))
+	return nil
+}
+
+// parseTemplateColor returns the hexadecimal color value, or black if value is empty.
+//
+// If value is not a hexadecimal color, it returns nil, error wrapping [ErrTemplateValue].
+func parseTemplateColor(value string) (*Color, error) {
+	if value == "" {
+		return &Black, nil
+	}
+	parsed, err := stringutils.ParseColorHex(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: color %q", ErrTemplateValue, value)
+	}
+	red, green, blue := utils.GetRGBComponents(parsed)
+	return &Color{R: red, G: green, B: blue}, nil
+}
@@ -0,0 +1,162 @@
+package img
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testTemplateYAML = `
+width: 200
+height: 100
+variables:
+  title: Hello
+  accent: ff0000
+layers:
+  - type: fill
+    color: ffffff
+  - type: fill
+    color: "{accent}"
+    width: 50
+  - type: gradient
+    x: 50
+    width: 50
+    height: 20
+    from: "000000"
+    to: ffffff
+    angle: 90
+  - type: shape
+    shape: circle
+    x: 100
+    width: 50
+    height: 50
+    color: 00ff00
+  - type: overlay
+    asset: red
+    x: 150
+    width: 50
+    height: 50
+    opacity: 0.5
+  - type: text
+    text: "{title}"
+    x: 50
+    y: 50
+    size: 30
+    align: right
+`
+
+func TestParseTemplate(t *testing.T) {
+	parsed, err := ParseTemplate([]byte(testTemplateYAML), templateExtensionYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Width != 200 || parsed.Height != 100 || len(parsed.Layers) != 6 || parsed.Variables["title"] != "Hello" {
+		t.Errorf("parsed template = %+v", parsed)
+	}
+	parsed, err = ParseTemplate([]byte(`{"width": 10, "height": 10, "layers": [{"type": "text", "text": "{w}", "size": 5, "maxlines": 1}]}`), templateExtensionJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Layers[0].MaxLines != 1 {
+		t.Errorf("parsed max lines = %d, want 1", parsed.Layers[0].MaxLines)
+	}
+
+	for _, data := range []string{
+		`{"width": 0, "height": 10}`,
+		`{"width": 10, "height": 10, "variables": {"Title": "x"}}`,
+		`{"width": 10, "height": 10, "layers": [{"type": "blur"}]}`,
+		`{"width": 10, "height": 10, "layers": [{"type": "fill", "colour": "fff"}]}`,
+		`{"width": 10, "height": 10, "layers": [{"type": "fill", "opacity": 2}]}`,
+		`{"width": 10, "height": 10, "layers": [{"type": "gradient", "from": "fff"}]}`,
+		`{"width": 10, "height": 10, "layers": [{"type": "shape", "shape": "star"}]}`,
+		`{"width": 10, "height": 10, "layers": [{"type": "text", "text": "x"}]}`,
+		`{"width": 10, "height": 10, "layers": [{"type": "text", "text": "x", "size": 5, "align": "justify"}]}`,
+		`{"width": 10, "height": 10, "layers": [{"type": "image", "crop": "top"}]}`,
+		`{"width": 10, "height": 10, "layers": [{"type": "overlay"}]}`,
+	} {
+		if _, err := ParseTemplate([]byte(data), templateExtensionJSON); err == nil {
+			t.Errorf("expected error for template %s", data)
+		}
+	}
+}
+
+func TestLoadTemplates(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "card.yml"), []byte(testTemplateYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadTemplates(dir); err != nil {
+		t.Fatal(err)
+	}
+	if FindTemplate("card") == nil || FindTemplate("missing") != nil {
+		t.Error("expected only the card template to be registered")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadTemplates(dir); err == nil {
+		t.Error("expected error for invalid template file")
+	}
+}
+
+func TestGenerateTemplate(t *testing.T) {
+	loadTestAssets(t)
+	template, err := ParseTemplate([]byte(testTemplateYAML), templateExtensionYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expand := func(text string) string {
+		return strings.ReplaceAll(strings.ReplaceAll(text, "{accent}", "0000ff"), "{title}", "Hi")
+	}
+	result, err := GenerateTemplate(&TemplateParams{Format: IMAGE_PNG, Template: template, Scale: 2, Expand: expand})
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := png.Decode(bytes.NewReader(result.Bytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := decoded.Bounds().Size(); size != image.Pt(400, 200) {
+		t.Fatalf("size = %v, want 400x200", size)
+	}
+	points := map[image.Point]color.RGBA{
+		{50, 100}:  {B: 0xFF, A: 0xFF},                   // Fill with the expanded color
+		{102, 20}:  {A: 0xFF},                            // Left end of the gradient
+		{298, 20}:  {R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}, // Right end of the gradient
+		{250, 50}:  {G: 0xFF, A: 0xFF},                   // Centre of the circle
+		{202, 98}:  {R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}, // Corner outside the circle
+		{350, 50}:  {R: 0xFF, G: 0x7F, B: 0x7F, A: 0xFF}, // Half opaque overlay
+		{120, 150}: {R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}, // Free space left of the right aligned text
+	}
+	for point, want := range points {
+		r, g, b, a := decoded.At(point.X, point.Y).RGBA()
+		got := color.RGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: uint8(a >> 8)}
+		if diff(got.R, want.R) > 8 || diff(got.G, want.G) > 8 || diff(got.B, want.B) > 8 || diff(got.A, want.A) > 8 {
+			t.Errorf("color at %v = %v, want %v", point, got, want)
+		}
+	}
+	// Right aligned text ends near the right edge of the canvas
+	ink := image.Rectangle{}
+	for y := 100; y < 200; y++ {
+		for x := 100; x < 400; x++ {
+			if r, _, _, _ := decoded.At(x, y).RGBA(); r>>8 < 0x80 {
+				ink = ink.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	if ink.Empty() || ink.Max.X < 380 || ink.Min.X < 200 {
+		t.Errorf("text ink = %v, want right aligned", ink)
+	}
+
+	invalid := func(text string) string { return strings.ReplaceAll(text, "{accent}", "blue") }
+	if _, err := GenerateTemplate(&TemplateParams{Format: IMAGE_PNG, Template: template, Scale: 1, Expand: invalid}); !errors.Is(err, ErrTemplateValue) {
+		t.Errorf("expected template value error for invalid color, got %v", err)
+	}
+}
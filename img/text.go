@@ -0,0 +1,504 @@
+package img
+
+import (
+	"bytes"
+	"image"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/fogleman/gg"
+	"github.com/go-text/typesetting/di"
+	otfont "github.com/go-text/typesetting/font"
+	ot "github.com/go-text/typesetting/font/opentype"
+	"github.com/go-text/typesetting/font/opentype/tables"
+	"github.com/go-text/typesetting/shaping"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/text/unicode/bidi"
+)
+
+// A textLine stores one line of shaped text.
+type textLine struct {
+	runs  shaping.Line // Runs of glyphs in logical order
+	width float64      // Advance of the whole line in pixels
+}
+
+// A textShaper shapes, wraps and draws text with a font.
+//
+// Text is shaped with HarfBuzz so that complex scripts e.g. Arabic and Devanagari get their joining forms,
+// ligatures and conjuncts. Runs of mixed direction are reordered with the Unicode bidirectional algorithm
+// and lines are broken at the break opportunities of Unicode line breaking (UAX #14).
+// Characters missing from the font are drawn with the first of the fallback fonts which has them,
+// see [SetFallbackFonts].
+//
+// A textShaper is not safe for concurrent use.
+type textShaper struct {
+	faces     fontChain
+	size      float64 // Font size in points
+	height    float64 // Height of a line in pixels
+	maxLines  int     // Maximum number of wrapped lines, 0 for no limit
+	ellipsis  bool    // Whether the last line ends with an ellipsis when text is truncated after maxLines
+	hyphenate bool    // Whether words wider than a line are broken with a hyphen
+	shaper    shaping.HarfbuzzShaper
+	segmenter shaping.Segmenter
+	wrapper   shaping.LineWrapper
+}
+
+// Characters added to wrapped lines
+const (
+	ellipsisRune = '…'
+	hyphenRune   = '-'
+)
+
+// A fontChain resolves each rune to the first face which has a glyph for it.
+type fontChain []*otfont.Face
+
+// ResolveFace returns the first face of the chain which has a glyph for the rune.
+// If no face has a glyph for the rune, it returns the first face.
+func (c fontChain) ResolveFace(char rune) *otfont.Face {
+	for _, face := range c {
+		if _, ok := face.NominalGlyph(char); ok {
+			return face
+		}
+	}
+	return c[0]
+}
+
+// resolveSpaces splits the spaces out of the runs of fallback faces and assigns them to the first face of the chain.
+//
+// Spaces do not change the face while segmenting text, but fallback fonts e.g. emoji fonts often have much wider spaces.
+func (c fontChain) resolveSpaces(runs []shaping.Input) []shaping.Input {
+	resolved := make([]shaping.Input, 0, len(runs))
+	for _, run := range runs {
+		if run.Face == c[0] {
+			resolved = append(resolved, run)
+			continue
+		}
+		for start := run.RunStart; start < run.RunEnd; {
+			space := unicode.IsSpace(run.Text[start])
+			end := start + 1
+			for end < run.RunEnd && unicode.IsSpace(run.Text[end]) == space {
+				end++
+			}
+			segment := run
+			segment.RunStart, segment.RunEnd = start, end
+			if space {
+				segment.Face = c[0]
+			}
+			resolved = append(resolved, segment)
+			start = end
+		}
+	}
+	return resolved
+}
+
+// newTextShaper returns a [textShaper] for the font followed by the fallback fonts with font size 0,
+// see [textShaper.setSize].
+func newTextShaper(font *otfont.Font) *textShaper {
+	faces := fontChain{otfont.NewFace(font)}
+	for _, fallback := range fallbackFonts {
+		if fallback != font {
+			faces = append(faces, otfont.NewFace(fallback))
+		}
+	}
+	return &textShaper{faces: faces}
+}
+
+// setSize sets the font size in points used to shape text.
+//
+// The line height of TrueType fonts is the height metric of their [truetype] face at that size, the same as of the
+// text of gg. The line height of other fonts is the sum of their ascender, descender and line gap at that size.
+func (s *textShaper) setSize(size float64) {
+	s.size = size
+	face := s.faces[0]
+	if font, exists := truetypeFonts[face.Font]; exists {
+		s.height = float64(truetype.NewFace(font, &truetype.Options{Size: size}).Metrics().Height) / 64
+		return
+	}
+	s.height = size
+	if extents, ok := face.FontHExtents(); ok && extents.Ascender-extents.Descender+extents.LineGap > 0 {
+		s.height = float64(extents.Ascender-extents.Descender+extents.LineGap) * size / float64(face.Upem())
+	}
+}
+
+// wrap shapes the text and breaks it into lines no wider than maxWidth.
+//
+// Line breaks in the text always start a new line and spaces around each line are trimmed.
+// Words wider than maxWidth overflow their line, unless hyphenation breaks them with a hyphen.
+// With a maximum number of lines, the remaining text is truncated and the last line ends with an ellipsis if enabled.
+func (s *textShaper) wrap(text string, maxWidth float64) []textLine {
+	var hyphen shaping.Output
+	if s.hyphenate {
+		// Leave room for the hyphen at the end of each line
+		hyphen = s.shapeRune(hyphenRune)
+		maxWidth -= fixedToFloat(hyphen.Advance)
+	}
+	maxFixed := fixed.Int26_6(math.MaxInt32)
+	if maxWidth*64 < float64(maxFixed) {
+		maxFixed = floatToFixed(math.Max(maxWidth, 0))
+	}
+	lines := []textLine{}
+	paragraphs := strings.Split(text, "\n")
+	for i, paragraph := range paragraphs {
+		if s.maxLines > 0 && len(lines) >= s.maxLines {
+			break
+		}
+		runes := []rune(strings.TrimSpace(paragraph))
+		if len(runes) == 0 {
+			lines = append(lines, textLine{})
+			continue
+		}
+		direction := paragraphDirection(runes)
+		input := shaping.Input{
+			Text:      runes,
+			RunEnd:    len(runes),
+			Direction: direction,
+			Face:      s.faces[0],
+			Size:      floatToFixed(s.size),
+		}
+		runs := []shaping.Output{}
+		for _, run := range s.faces.resolveSpaces(s.segmenter.Split(input, s.faces)) {
+			runs = append(runs, s.shape(run))
+		}
+		config := shaping.WrapConfig{Direction: direction, BreakPolicy: shaping.Never}
+		if s.hyphenate {
+			config.BreakPolicy = shaping.WhenNecessary
+		}
+		if s.maxLines > 0 {
+			config.TruncateAfterLines = s.maxLines - len(lines)
+			config.TextContinues = strings.TrimSpace(strings.Join(paragraphs[i+1:], "")) != ""
+			if s.ellipsis {
+				config.Truncator = s.shapeRune(ellipsisRune)
+			}
+		}
+		wrapped, _ := s.wrapper.WrapParagraphF(config, maxFixed, runes, shaping.NewSliceIterator(runs))
+		for _, wrappedLine := range wrapped {
+			// Wrapped lines are reused by the wrapper on the next paragraph, and the truncated
+			// line ends with an empty run without a face when there is no ellipsis
+			line := shaping.Line{}
+			for _, run := range wrappedLine {
+				if run.Face != nil {
+					line = append(line, run)
+				}
+			}
+			if s.hyphenate && breaksWord(runes, line) {
+				hyphen.VisualIndex = int32(len(line))
+				if direction.Progression() == di.TowardTopLeft {
+					hyphen.VisualIndex = -1
+				}
+				line = append(line, hyphen)
+			}
+			width := fixed.Int26_6(0)
+			for _, run := range line {
+				width += run.Advance
+			}
+			lines = append(lines, textLine{runs: line, width: fixedToFloat(width)})
+		}
+	}
+	return lines
+}
+
+// shapeRune shapes the single rune with the first face of the chain which has a glyph for it.
+func (s *textShaper) shapeRune(char rune) shaping.Output {
+	return s.shape(shaping.Input{
+		Text:      []rune{char},
+		RunEnd:    1,
+		Direction: di.DirectionLTR,
+		Face:      s.faces.ResolveFace(char),
+		Size:      floatToFixed(s.size),
+	})
+}
+
+// shape shapes the run with the font size of the shaper.
+//
+// HarfBuzz positions glyphs at the font size rounded up to whole pixels, so the run is shaped in font units and
+// scaled to the font size with the rounding of [truetype] faces. The advances of the glyphs of TrueType fonts are
+// the advances of their truetype faces plus the kerning of the shaper, so that plain text is laid out like by gg.
+func (s *textShaper) shape(input shaping.Input) shaping.Output {
+	upem := int(input.Face.Upem())
+	input.Size = fixed.I(upem)
+	output := s.shaper.Shape(input)
+	scale := fixed.Int26_6(0.5 + s.size*64)
+	toPixels := func(value fixed.Int26_6) fixed.Int26_6 {
+		return scaleFontUnits(value, scale, upem)
+	}
+	var face *glyphFace
+	if font, exists := truetypeFonts[input.Face.Font]; exists && !input.Direction.IsVertical() {
+		face = newGlyphFace(font, s.size)
+	}
+	for i := range output.Glyphs {
+		glyph := &output.Glyphs[i]
+		if face != nil {
+			nominal := fixed.Int26_6(input.Face.HorizontalAdvance(glyph.GlyphID) * 64)
+			glyph.XAdvance = face.advance(truetype.Index(glyph.GlyphID)) + toPixels(glyph.XAdvance-nominal)
+		} else {
+			glyph.XAdvance = toPixels(glyph.XAdvance)
+		}
+		glyph.YAdvance = toPixels(glyph.YAdvance)
+		glyph.XOffset, glyph.YOffset = toPixels(glyph.XOffset), toPixels(glyph.YOffset)
+		glyph.Width, glyph.Height = toPixels(glyph.Width), toPixels(glyph.Height)
+		glyph.XBearing, glyph.YBearing = toPixels(glyph.XBearing), toPixels(glyph.YBearing)
+		glyph.Advance = glyph.XAdvance
+		if input.Direction.IsVertical() {
+			glyph.Advance = glyph.YAdvance
+		}
+	}
+	bounds := &output.LineBounds
+	bounds.Ascent, bounds.Descent, bounds.Gap = toPixels(bounds.Ascent), toPixels(bounds.Descent), toPixels(bounds.Gap)
+	output.Size = scale
+	output.RecalculateAll()
+	return output
+}
+
+// scaleFontUnits converts a length in font units, as a 26.6 fixed point value, to pixels at the scale in
+// 26.6 fixed point pixels per em, rounded half away from zero like the lengths of [truetype] faces.
+func scaleFontUnits(value, scale fixed.Int26_6, upem int) fixed.Int26_6 {
+	length := int64(value) * int64(scale) / 64
+	if length >= 0 {
+		length += int64(upem) / 2
+	} else {
+		length -= int64(upem) / 2
+	}
+	return fixed.Int26_6(length / int64(upem))
+}
+
+// breaksWord returns true if the wrapped line of the paragraph text ends inside a word of an alphabetic script.
+//
+// Lines of ideographic scripts e.g. Chinese may break between any two characters without a hyphen.
+func breaksWord(text []rune, line shaping.Line) bool {
+	end := 0
+	for _, run := range line {
+		if runEnd := run.Runes.Offset + run.Runes.Count; runEnd > end {
+			end = runEnd
+		}
+	}
+	if end <= 0 || end >= len(text) {
+		return false
+	}
+	return isHyphenatable(text[end-1]) && isHyphenatable(text[end])
+}
+
+// isHyphenatable returns true if the character is a letter, mark or digit of a script which hyphenates words.
+func isHyphenatable(char rune) bool {
+	if !unicode.In(char, unicode.Letter, unicode.Mark, unicode.Digit) {
+		return false
+	}
+	return !unicode.In(char, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul, unicode.Thai, unicode.Lao, unicode.Khmer, unicode.Myanmar)
+}
+
+// drawLines draws the lines with given color as a block centered at x, y with given line spacing.
+//
+// Each line is centered horizontally. The block height is calculated like [gg.Context.DrawStringWrapped],
+// which also centers a line by its width in whole pixels.
+func (s *textShaper) drawLines(canvas *gg.Context, lines []textLine, color *Color, x, y, lineSpacing float64) {
+	baseline := y - s.blockHeight(len(lines), lineSpacing)/2 + s.height
+	for _, line := range lines {
+		drawLine(canvas, line, color, x-math.Floor(line.width)/2, baseline)
+		baseline += s.height * lineSpacing
+	}
+}
+
+// blockHeight returns the height of a block of n lines with given line spacing.
+func (s *textShaper) blockHeight(n int, lineSpacing float64) float64 {
+	if n == 0 {
+		return 0
+	}
+	return float64(n)*s.height*lineSpacing - (lineSpacing-1)*s.height
+}
+
+// drawLine draws the glyphs of the line with given color, its left edge at x and its baseline at y.
+//
+// Runs are drawn in visual order with the current transformation of the canvas.
+// Without transformation, outline glyphs of TrueType fonts are drawn by gg, see [glyphFace],
+// so that plain text looks the same as the text drawn by gg.
+// Color glyphs of bitmap and COLR version 0 emoji fonts keep their own colors.
+func drawLine(canvas *gg.Context, line textLine, color *Color, x, y float64) {
+	runs := append(shaping.Line{}, line.runs...)
+	sort.Slice(runs, func(i, j int) bool { return runs[i].VisualIndex < runs[j].VisualIndex })
+	// The pen is moved in 26.6 fixed point, like the pen of gg
+	dot := fixed.Point26_6{X: fixed.Int26_6(x * 64), Y: fixed.Int26_6(y * 64)}
+	identity := isIdentity(canvas)
+	for _, run := range runs {
+		scale := fixedToFloat(run.Size) / float64(run.Face.Upem())
+		var face *glyphFace
+		if font, exists := truetypeFonts[run.Face.Font]; exists && identity {
+			face = newGlyphFace(font, fixedToFloat(run.Size))
+		}
+		// Consecutive outline glyphs are drawn together from the pen position of the first one
+		start := dot
+		flush := func() {
+			if face != nil && len(face.glyphs) > 0 {
+				drawGlyphs(canvas, face, color, start)
+			}
+		}
+		for _, glyph := range run.Glyphs {
+			glyphX, glyphY := fixedToFloat(dot.X+glyph.XOffset), fixedToFloat(dot.Y-glyph.YOffset)
+			switch data := run.Face.GlyphData(glyph.GlyphID).(type) {
+			case otfont.GlyphOutline:
+				if face == nil {
+					fillOutline(canvas, data, color, glyphX, glyphY, scale)
+					break
+				}
+				if len(face.glyphs) == maxGlyphRunes {
+					flush()
+				}
+				if len(face.glyphs) == 0 {
+					start = dot
+				}
+				face.glyphs = append(face.glyphs, glyphPosition{
+					index:   truetype.Index(glyph.GlyphID),
+					xOffset: glyph.XOffset,
+					yOffset: glyph.YOffset,
+					advance: glyph.XAdvance,
+				})
+			case otfont.GlyphBitmap:
+				flush()
+				drawBitmapGlyph(canvas, data, glyph, color, glyphX, glyphY, scale)
+			case otfont.GlyphColor:
+				flush()
+				drawColorGlyph(canvas, run.Face, glyph.GlyphID, data, color, glyphX, glyphY, scale)
+			case otfont.GlyphSVG:
+				flush()
+				fillOutline(canvas, data.Outline, color, glyphX, glyphY, scale)
+			}
+			dot.X += glyph.Advance
+		}
+		flush()
+	}
+}
+
+// drawGlyphs draws the glyphs of the face with given color and the pen at dot, and clears them.
+func drawGlyphs(canvas *gg.Context, face *glyphFace, color *Color, dot fixed.Point26_6) {
+	var text strings.Builder
+	for i := range face.glyphs {
+		text.WriteRune(rune(i))
+	}
+	canvas.SetFontFace(face)
+	canvas.SetRGBA255(int(color.R), int(color.G), int(color.B), 0xFF)
+	canvas.DrawString(text.String(), fixedToFloat(dot.X), fixedToFloat(dot.Y))
+	face.glyphs = face.glyphs[:0]
+}
+
+// isIdentity returns true if the current transformation of the canvas leaves points unchanged.
+func isIdentity(canvas *gg.Context) bool {
+	x0, y0 := canvas.TransformPoint(0, 0)
+	x1, y1 := canvas.TransformPoint(1, 0)
+	x2, y2 := canvas.TransformPoint(0, 1)
+	return x0 == 0 && y0 == 0 && x1 == 1 && y1 == 0 && x2 == 0 && y2 == 1
+}
+
+// fillOutline fills the glyph outline with given color and its origin at x, y, see [drawOutline].
+func fillOutline(canvas *gg.Context, outline otfont.GlyphOutline, color *Color, x, y, scale float64) {
+	drawOutline(canvas, outline, x, y, scale)
+	canvas.SetRGBA255(int(color.R), int(color.G), int(color.B), 0xFF)
+	canvas.Fill()
+}
+
+// drawBitmapGlyph draws the image of a bitmap glyph e.g. a color emoji, scaled to the extents of the glyph
+// with its origin at x, y.
+//
+// Black and white bitmaps and images which cannot be decoded are drawn by their outline with given color, if any.
+func drawBitmapGlyph(canvas *gg.Context, bitmap otfont.GlyphBitmap, glyph shaping.Glyph, color *Color, x, y, scale float64) {
+	var picture image.Image
+	if bitmap.Format == otfont.PNG || bitmap.Format == otfont.JPG || bitmap.Format == otfont.TIFF {
+		picture, _, _ = image.Decode(bytes.NewReader(bitmap.Data))
+	}
+	width, height := fixedToFloat(glyph.Width), -fixedToFloat(glyph.Height)
+	if picture == nil || width <= 0 || height <= 0 {
+		if bitmap.Outline != nil {
+			fillOutline(canvas, *bitmap.Outline, color, x, y, scale)
+		}
+		return
+	}
+	bounds := picture.Bounds()
+	canvas.Push()
+	canvas.Translate(x+fixedToFloat(glyph.XBearing), y-fixedToFloat(glyph.YBearing))
+	canvas.Scale(width/float64(bounds.Dx()), height/float64(bounds.Dy()))
+	canvas.DrawImage(picture, -bounds.Min.X, -bounds.Min.Y)
+	canvas.Pop()
+}
+
+// drawColorGlyph draws a glyph of the COLR table of the face with its origin at x, y.
+//
+// COLR version 0 glyphs are drawn as their layers with the colors of the default palette,
+// where the foreground layers take given color. Other glyphs are drawn by their outline with given color.
+func drawColorGlyph(canvas *gg.Context, face *otfont.Face, glyphID otfont.GID, glyph otfont.GlyphColor, color *Color, x, y, scale float64) {
+	layers, ok := glyph.Paint.(tables.PaintColrLayersResolved)
+	if !ok || len(face.CPAL) == 0 {
+		if outline, ok := face.GlyphDataOutline(glyphID); ok {
+			fillOutline(canvas, outline, color, x, y, scale)
+		}
+		return
+	}
+	palette := face.CPAL[0]
+	for _, layer := range layers {
+		outline, ok := face.GlyphDataOutline(otfont.GID(layer.GlyphID))
+		if !ok {
+			continue
+		}
+		drawOutline(canvas, outline, x, y, scale)
+		if int(layer.PaletteIndex) < len(palette) {
+			record := palette[layer.PaletteIndex]
+			canvas.SetRGBA255(int(record.Red), int(record.Green), int(record.Blue), int(record.Alpha))
+		} else {
+			// Palette index 0xFFFF refers to the text color
+			canvas.SetRGBA255(int(color.R), int(color.G), int(color.B), 0xFF)
+		}
+		canvas.Fill()
+	}
+}
+
+// drawOutline adds the glyph outline to the current path of the canvas with its origin at x, y.
+//
+// The outline in font units is multiplied by scale, and its y axis is flipped to point down.
+func drawOutline(canvas *gg.Context, outline otfont.GlyphOutline, x, y, scale float64) {
+	point := func(p ot.SegmentPoint) (float64, float64) {
+		return x + float64(p.X)*scale, y - float64(p.Y)*scale
+	}
+	for _, segment := range outline.Segments {
+		x1, y1 := point(segment.Args[0])
+		switch segment.Op {
+		case ot.SegmentOpMoveTo:
+			canvas.ClosePath()
+			canvas.MoveTo(x1, y1)
+		case ot.SegmentOpLineTo:
+			canvas.LineTo(x1, y1)
+		case ot.SegmentOpQuadTo:
+			x2, y2 := point(segment.Args[1])
+			canvas.QuadraticTo(x1, y1, x2, y2)
+		case ot.SegmentOpCubeTo:
+			x2, y2 := point(segment.Args[1])
+			x3, y3 := point(segment.Args[2])
+			canvas.CubicTo(x1, y1, x2, y2, x3, y3)
+		}
+	}
+	canvas.ClosePath()
+}
+
+// paragraphDirection returns the direction of the first character with a strong direction in the text.
+//
+// Text without such a character is left to right.
+func paragraphDirection(text []rune) di.Direction {
+	for _, char := range text {
+		properties, _ := bidi.LookupRune(char)
+		switch properties.Class() {
+		case bidi.L:
+			return di.DirectionLTR
+		case bidi.R, bidi.AL:
+			return di.DirectionRTL
+		}
+	}
+	return di.DirectionLTR
+}
+
+// floatToFixed converts pixels to a 26.6 fixed point value.
+func floatToFixed(value float64) fixed.Int26_6 {
+	return fixed.Int26_6(math.Round(value * 64))
+}
+
+// fixedToFloat converts a 26.6 fixed point value to pixels.
+func fixedToFloat(value fixed.Int26_6) float64 {
+	return float64(value) / 64
+}
@@ -0,0 +1,127 @@
+package img
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/go-text/typesetting/di"
+	otfont "github.com/go-text/typesetting/font"
+	"github.com/go-text/typesetting/shaping"
+)
+
+func TestTextShaperWrap(t *testing.T) {
+	font, err := getFont(DefaultFont)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shaper := newTextShaper(font)
+	shaper.setSize(20)
+	// Width of the given text on a single line
+	width := func(text string) float64 {
+		return shaper.wrap(text, math.Inf(1))[0].width
+	}
+	tests := []struct {
+		name     string
+		text     string
+		maxWidth float64
+		want     int
+	}{
+		{name: "Fits on one line", text: "300 x 200", maxWidth: 400, want: 1},
+		{name: "Breaks between words", text: "Hello placeholder world", maxWidth: width("Hello placeholder"), want: 2},
+		{name: "Keeps long word whole", text: "Supercalifragilistic", maxWidth: 20, want: 1},
+		{name: "Line breaks start new lines", text: "Hello\nworld", maxWidth: 400, want: 2},
+		{name: "Breaks between ideographs", text: "漢字漢字漢字漢字", maxWidth: width("漢字"), want: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shaper.wrap(tt.text, tt.maxWidth); len(got) != tt.want {
+				t.Errorf("wrap() lines = %d, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestTextShaperVisualOrder(t *testing.T) {
+	font, err := getFont(DefaultFont)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shaper := newTextShaper(font)
+	shaper.setSize(20)
+	tests := []struct {
+		text string
+		want []int
+	}{
+		{text: "ab אב", want: []int{0, 1, 2, 4, 3}},
+		{text: "אב ab", want: []int{3, 4, 2, 1, 0}},
+		{text: "שלום", want: []int{3, 2, 1, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			lines := shaper.wrap(tt.text, math.Inf(1))
+			if len(lines) != 1 {
+				t.Fatalf("wrap() lines = %d, want 1", len(lines))
+			}
+			runs := lines[0].runs
+			sort.Slice(runs, func(i, j int) bool { return runs[i].VisualIndex < runs[j].VisualIndex })
+			got := []int{}
+			for _, run := range runs {
+				for _, glyph := range run.Glyphs {
+					got = append(got, glyph.TextIndex())
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("visual order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParagraphDirection(t *testing.T) {
+	tests := []struct {
+		text string
+		want di.Direction
+	}{
+		{text: "Hello", want: di.DirectionLTR},
+		{text: "مرحبا Hello", want: di.DirectionRTL},
+		{text: "300 שלום", want: di.DirectionRTL},
+		{text: "123", want: di.DirectionLTR},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := paragraphDirection([]rune(tt.text)); got != tt.want {
+				t.Errorf("paragraphDirection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFontChain(t *testing.T) {
+	chain := fontChain{otfont.NewFace(fonts[DefaultFont]), otfont.NewFace(fonts["Go-Bold"])}
+	if face := chain.ResolveFace('A'); face != chain[0] {
+		t.Error("expected first face for a glyph of the first font")
+	}
+	if face := chain.ResolveFace('漢'); face != chain[0] {
+		t.Error("expected first face for a glyph missing from all fonts")
+	}
+
+	text := []rune("ab  cd")
+	runs := chain.resolveSpaces([]shaping.Input{
+		{Text: text, RunStart: 0, RunEnd: 1, Face: chain[0]},
+		{Text: text, RunStart: 1, RunEnd: 6, Face: chain[1]},
+	})
+	want := []struct {
+		start, end int
+		face       *otfont.Face
+	}{{0, 1, chain[0]}, {1, 2, chain[1]}, {2, 4, chain[0]}, {4, 6, chain[1]}}
+	if len(runs) != len(want) {
+		t.Fatalf("resolveSpaces() runs = %d, want %d", len(runs), len(want))
+	}
+	for i, run := range runs {
+		if run.RunStart != want[i].start || run.RunEnd != want[i].end || run.Face != want[i].face {
+			t.Errorf("run %d = [%d, %d), want [%d, %d)", i, run.RunStart, run.RunEnd, want[i].start, want[i].end)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package img
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/image/draw"
+)
+
+// Maximum dimension of the image encoded by ThumbHash, larger images are downscaled before encoding
+const thumbHashMaxEncodeSize = 100
+
+// Dimension of the longer side of the image decoded from ThumbHash
+const thumbHashDecodeSize = 32
+
+// ThumbHashAspectRatio returns the approximate width / height ratio of the image encoded in the ThumbHash.
+//
+// If the hash is malformed, it returns 0.0, error.
+func ThumbHashAspectRatio(hash []byte) (float64, error) {
+	if len(hash) < 5 {
+		return 0.0, errors.New("thumbhash must have at least 5 bytes")
+	}
+	hasAlpha := hash[2]&0x80 != 0
+	isLandscape := hash[4]&0x80 != 0
+	lx, ly := thumbHashLuminanceSize(hash[3]&7, hasAlpha, isLandscape)
+	return float64(lx) / float64(ly), nil
+}
+
+// thumbHashLuminanceSize returns the number of luminance components in each direction stored in the header.
+func thumbHashLuminanceSize(stored byte, hasAlpha, isLandscape bool) (lx, ly int) {
+	longer := 7
+	if hasAlpha {
+		longer = 5
+	}
+	if isLandscape {
+		return longer, int(stored)
+	}
+	return int(stored), longer
+}
+
+// DecodeThumbHash decodes the ThumbHash into an image, 32 pixels on the longer side, with the aspect ratio of the hash.
+//
+// If the hash is malformed, it returns nil, error.
+func DecodeThumbHash(hash []byte) (*image.NRGBA, error) {
+	if len(hash) < 5 {
+		return nil, errors.New("thumbhash must have at least 5 bytes")
+	}
+	// Read the constants
+	header24 := int(hash[0]) | int(hash[1])<<8 | int(hash[2])<<16
+	header16 := int(hash[3]) | int(hash[4])<<8
+	lDC := float64(header24&63) / 63
+	pDC := float64(header24>>6&63)/31.5 - 1
+	qDC := float64(header24>>12&63)/31.5 - 1
+	lScale := float64(header24>>18&31) / 31
+	hasAlpha := header24>>23 != 0
+	pScale := float64(header16>>3&63) / 63
+	qScale := float64(header16>>9&63) / 63
+	isLandscape := header16>>15 != 0
+	lx, ly := thumbHashLuminanceSize(byte(header16&7), hasAlpha, isLandscape)
+	if lx == 0 || ly == 0 {
+		return nil, errors.New("thumbhash has no luminance components")
+	}
+	ratio := float64(lx) / float64(ly)
+	lx, ly = int(math.Max(3, float64(lx))), int(math.Max(3, float64(ly)))
+
+	acStart := 5
+	aDC, aScale := 1.0, 0.0
+	if hasAlpha {
+		if len(hash) < 6 {
+			return nil, errors.New("thumbhash with alpha must have at least 6 bytes")
+		}
+		acStart = 6
+		aDC = float64(hash[5]&15) / 15
+		aScale = float64(hash[5]>>4) / 15
+	}
+
+	// Read the varying factors (boost saturation by 1.25x to compensate for quantization)
+	acIndex := 0
+	var errShort error
+	decodeChannel := func(nx, ny int, scale float64) []float64 {
+		ac := []float64{}
+		for cy := 0; cy < ny; cy++ {
+			cx := 1
+			if cy > 0 {
+				cx = 0
+			}
+			for ; cx*ny < nx*(ny-cy); cx++ {
+				index := acStart + acIndex>>1
+				if index >= len(hash) {
+					errShort = errors.New("thumbhash is too short for its components")
+					return ac
+				}
+				ac = append(ac, (float64(hash[index]>>((acIndex&1)<<2)&15)/7.5-1)*scale)
+				acIndex++
+			}
+		}
+		return ac
+	}
+	lAC := decodeChannel(lx, ly, lScale)
+	pAC := decodeChannel(3, 3, pScale*1.25)
+	qAC := decodeChannel(3, 3, qScale*1.25)
+	var aAC []float64
+	if hasAlpha {
+		aAC = decodeChannel(5, 5, aScale)
+	}
+	if errShort != nil {
+		return nil, errShort
+	}
+
+	// Decode using the DCT into RGB
+	w, h := thumbHashDecodeSize, thumbHashDecodeSize
+	if ratio > 1 {
+		h = int(math.Round(thumbHashDecodeSize / ratio))
+	} else {
+		w = int(math.Round(thumbHashDecodeSize * ratio))
+	}
+	n := 3
+	if hasAlpha {
+		n = 5
+	}
+	fx := make([]float64, int(math.Max(float64(lx), float64(n))))
+	fy := make([]float64, int(math.Max(float64(ly), float64(n))))
+	decoded := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			l, p, q, a := lDC, pDC, qDC, aDC
+
+			// Precompute the coefficients
+			for cx := range fx {
+				fx[cx] = math.Cos(math.Pi / float64(w) * (float64(x) + 0.5) * float64(cx))
+			}
+			for cy := range fy {
+				fy[cy] = math.Cos(math.Pi / float64(h) * (float64(y) + 0.5) * float64(cy))
+			}
+
+			// Decode L
+			j := 0
+			for cy := 0; cy < ly; cy++ {
+				fy2 := fy[cy] * 2
+				for cx := boolToInt(cy == 0); cx*ly < lx*(ly-cy); cx++ {
+					l += lAC[j] * fx[cx] * fy2
+					j++
+				}
+			}
+
+			// Decode P and Q
+			j = 0
+			for cy := 0; cy < 3; cy++ {
+				fy2 := fy[cy] * 2
+				for cx := boolToInt(cy == 0); cx < 3-cy; cx++ {
+					f := fx[cx] * fy2
+					p += pAC[j] * f
+					q += qAC[j] * f
+					j++
+				}
+			}
+
+			// Decode A
+			if hasAlpha {
+				j = 0
+				for cy := 0; cy < 5; cy++ {
+					fy2 := fy[cy] * 2
+					for cx := boolToInt(cy == 0); cx < 5-cy; cx++ {
+						a += aAC[j] * fx[cx] * fy2
+						j++
+					}
+				}
+			}
+
+			// Convert to RGB
+			b := l - 2.0/3.0*p
+			r := (3*l - b + q) / 2
+			g := r - q
+			decoded.SetNRGBA(x, y, color.NRGBA{R: unitToByte(r), G: unitToByte(g), B: unitToByte(b), A: unitToByte(a)})
+		}
+	}
+	return decoded, nil
+}
+
+// EncodeThumbHash encodes the image into a ThumbHash.
+//
+// Images larger than 100x100 are downscaled to fit in 100x100 before encoding.
+// If the image is empty, it returns nil, error.
+func EncodeThumbHash(src image.Image) ([]byte, error) {
+	bounds := src.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return nil, errors.New("thumbhash image must not be empty")
+	}
+	w, h := bounds.Dx(), bounds.Dy()
+	if w > thumbHashMaxEncodeSize || h > thumbHashMaxEncodeSize {
+		scale := thumbHashMaxEncodeSize / math.Max(float64(w), float64(h))
+		w, h = int(math.Max(1, math.Round(float64(w)*scale))), int(math.Max(1, math.Round(float64(h)*scale)))
+	}
+	pixels := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.BiLinear.Scale(pixels, pixels.Bounds(), src, bounds, draw.Src, nil)
+
+	// Determine the average color
+	var avgR, avgG, avgB, avgA float64
+	for i := 0; i < w*h; i++ {
+		alpha := float64(pixels.Pix[i*4+3]) / 0xFF
+		avgR += alpha / 0xFF * float64(pixels.Pix[i*4])
+		avgG += alpha / 0xFF * float64(pixels.Pix[i*4+1])
+		avgB += alpha / 0xFF * float64(pixels.Pix[i*4+2])
+		avgA += alpha
+	}
+	if avgA > 0 {
+		avgR /= avgA
+		avgG /= avgA
+		avgB /= avgA
+	}
+
+	hasAlpha := avgA < float64(w*h)
+	lLimit := 7.0
+	if hasAlpha {
+		// Use fewer luminance bits if there's alpha
+		lLimit = 5
+	}
+	longer := math.Max(float64(w), float64(h))
+	lx := int(math.Max(1, math.Round(lLimit*float64(w)/longer)))
+	ly := int(math.Max(1, math.Round(lLimit*float64(h)/longer)))
+
+	// Convert the image from RGBA to LPQA (composite atop the average color)
+	l, p, q, a := make([]float64, w*h), make([]float64, w*h), make([]float64, w*h), make([]float64, w*h)
+	for i := 0; i < w*h; i++ {
+		alpha := float64(pixels.Pix[i*4+3]) / 0xFF
+		r := avgR*(1-alpha) + alpha/0xFF*float64(pixels.Pix[i*4])
+		g := avgG*(1-alpha) + alpha/0xFF*float64(pixels.Pix[i*4+1])
+		b := avgB*(1-alpha) + alpha/0xFF*float64(pixels.Pix[i*4+2])
+		l[i] = (r + g + b) / 3
+		p[i] = (r+g)/2 - b
+		q[i] = r - g
+		a[i] = alpha
+	}
+
+	// Encode using the DCT into DC (constant) and normalized AC (varying) terms
+	encodeChannel := func(channel []float64, nx, ny int) (dc float64, ac []float64, scale float64) {
+		fx := make([]float64, w)
+		for cy := 0; cy < ny; cy++ {
+			for cx := 0; cx*ny < nx*(ny-cy); cx++ {
+				f := 0.0
+				for x := 0; x < w; x++ {
+					fx[x] = math.Cos(math.Pi / float64(w) * float64(cx) * (float64(x) + 0.5))
+				}
+				for y := 0; y < h; y++ {
+					fy := math.Cos(math.Pi / float64(h) * float64(cy) * (float64(y) + 0.5))
+					for x := 0; x < w; x++ {
+						f += channel[x+y*w] * fx[x] * fy
+					}
+				}
+				f /= float64(w * h)
+				if cx > 0 || cy > 0 {
+					ac = append(ac, f)
+					scale = math.Max(scale, math.Abs(f))
+				} else {
+					dc = f
+				}
+			}
+		}
+		if scale > 0 {
+			for i := range ac {
+				ac[i] = 0.5 + 0.5/scale*ac[i]
+			}
+		}
+		return dc, ac, scale
+	}
+	lDC, lAC, lScale := encodeChannel(l, int(math.Max(3, float64(lx))), int(math.Max(3, float64(ly))))
+	pDC, pAC, pScale := encodeChannel(p, 3, 3)
+	qDC, qAC, qScale := encodeChannel(q, 3, 3)
+	var aDC, aScale float64
+	var aAC []float64
+	if hasAlpha {
+		aDC, aAC, aScale = encodeChannel(a, 5, 5)
+	}
+
+	// Write the constants
+	isLandscape := w > h
+	header24 := round(63*lDC) | round(31.5+31.5*pDC)<<6 | round(31.5+31.5*qDC)<<12 | round(31*lScale)<<18 | boolToInt(hasAlpha)<<23
+	stored := lx
+	if isLandscape {
+		stored = ly
+	}
+	header16 := stored | round(63*pScale)<<3 | round(63*qScale)<<9 | boolToInt(isLandscape)<<15
+	hash := []byte{byte(header24), byte(header24 >> 8), byte(header24 >> 16), byte(header16), byte(header16 >> 8)}
+	channels := [][]float64{lAC, pAC, qAC}
+	if hasAlpha {
+		hash = append(hash, byte(round(15*aDC)|round(15*aScale)<<4))
+		channels = append(channels, aAC)
+	}
+
+	// Write the varying factors
+	acStart, acIndex := len(hash), 0
+	for _, ac := range channels {
+		for _, f := range ac {
+			if acStart+acIndex>>1 >= len(hash) {
+				hash = append(hash, 0)
+			}
+			hash[acStart+acIndex>>1] |= byte(round(15*f) << ((acIndex & 1) << 2))
+			acIndex++
+		}
+	}
+	return hash, nil
+}
+
+// round rounds the non-negative value to the nearest integer.
+func round(value float64) int {
+	return int(math.Round(value))
+}
+
+// boolToInt returns 1 for true and 0 for false.
+func boolToInt(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// unitToByte converts the value in range [0, 1] into a byte, clamping values outside the range.
+func unitToByte(value float64) uint8 {
+	return uint8(math.Max(0, 0xFF*math.Min(1, value)))
+}
@@ -0,0 +1,58 @@
+package img
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestThumbHashRoundTrip(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		color         color.NRGBA
+	}{
+		{name: "Landscape", width: 200, height: 100, color: color.NRGBA{R: 0x33, G: 0x66, B: 0x99, A: 0xFF}},
+		{name: "Portrait", width: 60, height: 90, color: color.NRGBA{R: 0xCC, G: 0x44, B: 0x22, A: 0xFF}},
+		{name: "Transparent", width: 50, height: 50, color: color.NRGBA{R: 0x10, G: 0xA0, B: 0x30, A: 0x80}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := image.NewNRGBA(image.Rect(0, 0, tt.width, tt.height))
+			draw.Draw(src, src.Bounds(), image.NewUniform(tt.color), image.Point{}, draw.Src)
+
+			hash, err := EncodeThumbHash(src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ratio, err := ThumbHashAspectRatio(hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := float64(tt.width) / float64(tt.height); ratio < expected*0.8 || ratio > expected*1.25 {
+				t.Errorf("expected aspect ratio ~ %.2f, actual aspect ratio = %.2f", expected, ratio)
+			}
+
+			decoded, err := DecodeThumbHash(hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if size := decoded.Bounds().Size(); size.X > thumbHashDecodeSize || size.Y > thumbHashDecodeSize {
+				t.Errorf("expected size to fit in %dx%d, actual size = %dx%d", thumbHashDecodeSize, thumbHashDecodeSize, size.X, size.Y)
+			}
+			actual := decoded.NRGBAAt(decoded.Bounds().Dx()/2, decoded.Bounds().Dy()/2)
+			if diff(tt.color.R, actual.R) > 12 || diff(tt.color.G, actual.G) > 12 || diff(tt.color.B, actual.B) > 12 || diff(tt.color.A, actual.A) > 12 {
+				t.Errorf("expected color = %v, actual color = %v", tt.color, actual)
+			}
+		})
+	}
+}
+
+func TestDecodeThumbHashInvalid(t *testing.T) {
+	for _, hash := range [][]byte{nil, {1, 2, 3}, {0x1, 0x2, 0x3, 0x47, 0x0}} {
+		if _, err := DecodeThumbHash(hash); err == nil {
+			t.Errorf("expected error for hash %v", hash)
+		}
+	}
+}
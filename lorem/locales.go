@@ -0,0 +1,106 @@
+package lorem
+
+// Default locale of generated text
+const DefaultLocale = "en"
+
+// A locale stores the words and formats of generated text in a language and region.
+type locale struct {
+	firstNames       []string
+	lastNames        []string
+	topics           []string // Subjects of titles
+	titles           []string // Title templates with {topic} and {number} placeholders
+	currency         string   // Currency symbol of prices
+	currencyBefore   bool     // Whether the currency symbol is written before the amount
+	decimalSeparator string   // Separator of the cents of prices
+}
+
+// Mapping of a locale name to its words and formats
+var locales = map[string]*locale{
+	"en": {
+		firstNames: []string{"Olivia", "Liam", "Emma", "Noah", "Ava", "James", "Sophia", "Lucas", "Mia", "Ethan", "Grace", "Henry"},
+		lastNames:  []string{"Smith", "Johnson", "Brown", "Taylor", "Miller", "Wilson", "Clark", "Walker", "Young", "Harris", "Lewis", "Carter"},
+		topics:     []string{"Photography", "Gardening", "Product Design", "Remote Work", "Home Cooking", "Train Travel", "Personal Finance", "Urban Cycling"},
+		titles: []string{
+			"{number} Tips for Better {topic}",
+			"The Complete Guide to {topic}",
+			"Why {topic} Matters More Than Ever",
+			"{topic}: What You Need to Know",
+			"How to Get Started with {topic}",
+		},
+		currency:         "$",
+		currencyBefore:   true,
+		decimalSeparator: ".",
+	},
+	"de": {
+		firstNames: []string{"Lena", "Jonas", "Mia", "Leon", "Hannah", "Felix", "Emilia", "Paul", "Lea", "Maximilian", "Anna", "Lukas"},
+		lastNames:  []string{"Müller", "Schmidt", "Schneider", "Fischer", "Weber", "Meyer", "Wagner", "Becker", "Schulz", "Hoffmann", "Koch", "Richter"},
+		topics:     []string{"Fotografie", "Gartenarbeit", "Produktdesign", "Homeoffice", "Hausmannskost", "Bahnreisen", "Geldanlage", "Radfahren in der Stadt"},
+		titles: []string{
+			"{number} Tipps rund um {topic}",
+			"Der komplette Ratgeber: {topic}",
+			"Warum {topic} wichtiger ist denn je",
+			"{topic}: Das müssen Sie wissen",
+			"So gelingt der Einstieg: {topic}",
+		},
+		currency:         "€",
+		decimalSeparator: ",",
+	},
+	"fr": {
+		firstNames: []string{"Louise", "Gabriel", "Emma", "Raphaël", "Jade", "Louis", "Alice", "Arthur", "Chloé", "Jules", "Léa", "Hugo"},
+		lastNames:  []string{"Martin", "Bernard", "Dubois", "Thomas", "Robert", "Richard", "Petit", "Durand", "Leroy", "Moreau", "Simon", "Laurent"},
+		topics:     []string{"la photographie", "le jardinage", "le design produit", "le télétravail", "la cuisine maison", "le voyage en train", "l'épargne", "le vélo en ville"},
+		titles: []string{
+			"{number} conseils pour {topic}",
+			"Tout savoir sur {topic}",
+			"Pourquoi {topic} change tout",
+			"{topic} : ce qu'il faut savoir",
+			"Bien débuter avec {topic}",
+		},
+		currency:         "€",
+		decimalSeparator: ",",
+	},
+	"es": {
+		firstNames: []string{"Lucía", "Hugo", "Sofía", "Mateo", "Martina", "Martín", "María", "Lucas", "Paula", "Leo", "Valeria", "Daniel"},
+		lastNames:  []string{"García", "Rodríguez", "González", "Fernández", "López", "Martínez", "Sánchez", "Pérez", "Gómez", "Martín", "Jiménez", "Ruiz"},
+		topics:     []string{"la fotografía", "la jardinería", "el diseño de producto", "el teletrabajo", "la cocina casera", "el viaje en tren", "el ahorro", "la bicicleta urbana"},
+		titles: []string{
+			"{number} consejos para {topic}",
+			"Todo sobre {topic}",
+			"Por qué {topic} lo cambia todo",
+			"{topic}: lo que debes saber",
+			"Cómo empezar con {topic}",
+		},
+		currency:         "€",
+		decimalSeparator: ",",
+	},
+	"it": {
+		firstNames: []string{"Sofia", "Leonardo", "Giulia", "Francesco", "Aurora", "Alessandro", "Ginevra", "Lorenzo", "Alice", "Mattia", "Beatrice", "Tommaso"},
+		lastNames:  []string{"Rossi", "Russo", "Ferrari", "Esposito", "Bianchi", "Romano", "Colombo", "Ricci", "Marino", "Greco", "Bruno", "Gallo"},
+		topics:     []string{"la fotografia", "il giardinaggio", "il design di prodotto", "il lavoro da remoto", "la cucina di casa", "il viaggio in treno", "il risparmio", "la bici in città"},
+		titles: []string{
+			"{number} consigli per {topic}",
+			"Guida completa: {topic}",
+			"Perché {topic} cambia tutto",
+			"{topic}: cosa sapere",
+			"Come iniziare con {topic}",
+		},
+		currency:         "€",
+		decimalSeparator: ",",
+	},
+}
+
+// Words of lorem ipsum text
+var loremWords = []string{
+	"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do",
+	"eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore", "magna", "aliqua", "enim",
+	"ad", "minim", "veniam", "quis", "nostrud", "exercitation", "ullamco", "laboris", "nisi", "aliquip",
+	"ex", "ea", "commodo", "consequat", "duis", "aute", "irure", "in", "reprehenderit", "voluptate",
+	"velit", "esse", "cillum", "fugiat", "nulla", "pariatur", "excepteur", "sint", "occaecat", "cupidatat",
+	"non", "proident", "sunt", "culpa", "qui", "officia", "deserunt", "mollit", "anim", "id", "est", "laborum",
+}
+
+// IsLocale returns true if text can be generated in the locale with given name e.g. en or de, otherwise it returns false.
+func IsLocale(name string) bool {
+	_, exists := locales[name]
+	return exists
+}
@@ -0,0 +1,139 @@
+// Package lorem provides generators of filler text for placeholder images.
+//
+// A generator is selected by a keyword, e.g. lorem:12 generates 12 words of lorem ipsum,
+// and name, title and price generate a person name, a headline and a price in the conventions of a locale.
+// The generated text is picked by the given random source, so the same seed always generates the same text.
+package lorem
+
+import (
+	"errors"
+	"math/rand"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Constants for generator keywords
+const (
+	KeywordLorem = "lorem"
+	KeywordName  = "name"
+	KeywordTitle = "title"
+	KeywordPrice = "price"
+)
+
+// Delimiter of the word count in lorem keyword e.g. lorem:12
+const wordsDelimiter = ":"
+
+// Limits of the word count of lorem ipsum
+const (
+	DefaultWords = 8
+	MaxWords     = 200
+)
+
+// Generation Errors
+var (
+	ErrUnknownKeyword = errors.New("unknown generator keyword")
+	ErrInvalidWords   = errors.New("word count must be in range [1, " + strconv.Itoa(MaxWords) + "]")
+	ErrUnknownLocale  = errors.New("unknown locale")
+)
+
+// IsKeyword returns true if the text selects a generator e.g. lorem, lorem:12 or name, otherwise it returns false.
+func IsKeyword(text string) bool {
+	switch text {
+	case KeywordLorem, KeywordName, KeywordTitle, KeywordPrice:
+		return true
+	}
+	return strings.HasPrefix(text, KeywordLorem+wordsDelimiter)
+}
+
+// Generate returns the text generated by the generator of keyword in given locale, picked by random.
+//
+// If the keyword, its word count or the locale is invalid, it returns "", error.
+func Generate(keyword, localeName string, random *rand.Rand) (string, error) {
+	locale, exists := locales[localeName]
+	if !exists {
+		return "", ErrUnknownLocale
+	}
+	switch keyword {
+	case KeywordLorem:
+		return words(DefaultWords, random), nil
+	case KeywordName:
+		return pick(locale.firstNames, random) + " " + pick(locale.lastNames, random), nil
+	case KeywordTitle:
+		return title(locale, random), nil
+	case KeywordPrice:
+		return price(locale, random), nil
+	}
+	countValue := strings.TrimPrefix(keyword, KeywordLorem+wordsDelimiter)
+	if countValue == keyword {
+		return "", ErrUnknownKeyword
+	}
+	count, err := strconv.Atoi(countValue)
+	if err != nil || count < 1 || count > MaxWords {
+		return "", ErrInvalidWords
+	}
+	return words(count, random), nil
+}
+
+// words returns count words of lorem ipsum as sentences of 4 to 10 words.
+func words(count int, random *rand.Rand) string {
+	var builder strings.Builder
+	for count > 0 {
+		length := 4 + random.Intn(7)
+		if length > count {
+			length = count
+		}
+		if builder.Len() > 0 {
+			builder.WriteString(" ")
+		}
+		previous := ""
+		for i := 0; i < length; i++ {
+			word := pick(loremWords, random)
+			for word == previous {
+				word = pick(loremWords, random)
+			}
+			previous = word
+			if i == 0 {
+				word = capitalize(word)
+			} else {
+				builder.WriteString(" ")
+			}
+			builder.WriteString(word)
+		}
+		builder.WriteString(".")
+		count -= length
+	}
+	return builder.String()
+}
+
+// title returns a headline of a random template of the locale about a random topic.
+func title(locale *locale, random *rand.Rand) string {
+	template := pick(locale.titles, random)
+	topic := pick(locale.topics, random)
+	if strings.HasPrefix(template, "{topic}") {
+		topic = capitalize(topic)
+	}
+	return strings.NewReplacer("{topic}", topic, "{number}", strconv.Itoa(3+random.Intn(10))).Replace(template)
+}
+
+// price returns a random price below 500 in the currency format of the locale e.g. $24.99 or 24,99 €.
+func price(locale *locale, random *rand.Rand) string {
+	cents := []string{"99", "95", "49", "00"}
+	amount := strconv.Itoa(1+random.Intn(499)) + locale.decimalSeparator + pick(cents, random)
+	if locale.currencyBefore {
+		return locale.currency + amount
+	}
+	return amount + " " + locale.currency
+}
+
+// pick returns a random element of values.
+func pick(values []string, random *rand.Rand) string {
+	return values[random.Intn(len(values))]
+}
+
+// capitalize returns the text with its first letter in upper case.
+func capitalize(text string) string {
+	first, size := utf8.DecodeRuneInString(text)
+	return string(unicode.ToUpper(first)) + text[size:]
+}
@@ -0,0 +1,99 @@
+package lorem
+
+import (
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestIsKeyword(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{text: "lorem", want: true},
+		{text: "lorem:12", want: true},
+		{text: "name", want: true},
+		{text: "title", want: true},
+		{text: "price", want: true},
+		{text: "Lorem", want: false},
+		{text: "first name", want: false},
+		{text: "300 x 200", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := IsKeyword(tt.text); got != tt.want {
+				t.Errorf("IsKeyword() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	for name := range locales {
+		for _, keyword := range []string{KeywordLorem, KeywordName, KeywordTitle, KeywordPrice, "lorem:30"} {
+			first, err := Generate(keyword, name, rand.New(rand.NewSource(42)))
+			if err != nil {
+				t.Fatalf("%s %s: %v", name, keyword, err)
+			}
+			second, _ := Generate(keyword, name, rand.New(rand.NewSource(42)))
+			if first == "" || first != second {
+				t.Errorf("%s %s: expected same non-empty text for same seed, got %q and %q", name, keyword, first, second)
+			}
+			if strings.ContainsAny(first, "{}") {
+				t.Errorf("%s %s: unexpected placeholder in %q", name, keyword, first)
+			}
+		}
+	}
+}
+
+func TestGenerateLorem(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for _, count := range []int{1, 4, 12, 200} {
+		text, err := Generate("lorem:"+strconv.Itoa(count), DefaultLocale, random)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if words := len(strings.Fields(text)); words != count {
+			t.Errorf("lorem:%d words = %d", count, words)
+		}
+		if !regexp.MustCompile(`^[A-Z].*\.$`).MatchString(text) {
+			t.Errorf("lorem:%d expected sentences, got %q", count, text)
+		}
+	}
+	for _, keyword := range []string{"lorem:0", "lorem:201", "lorem:x", "lorem:"} {
+		if _, err := Generate(keyword, DefaultLocale, random); err != ErrInvalidWords {
+			t.Errorf("%s error = %v, want %v", keyword, err, ErrInvalidWords)
+		}
+	}
+	if _, err := Generate("ipsum", DefaultLocale, random); err != ErrUnknownKeyword {
+		t.Errorf("error = %v, want %v", err, ErrUnknownKeyword)
+	}
+	if _, err := Generate(KeywordName, "xx", random); err != ErrUnknownLocale {
+		t.Errorf("error = %v, want %v", err, ErrUnknownLocale)
+	}
+}
+
+func TestGeneratePrice(t *testing.T) {
+	tests := []struct {
+		locale  string
+		pattern string
+	}{
+		{locale: "en", pattern: `^\$[0-9]+\.[0-9]{2}$`},
+		{locale: "de", pattern: `^[0-9]+,[0-9]{2} €$`},
+		{locale: "fr", pattern: `^[0-9]+,[0-9]{2} €$`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			got, err := Generate(KeywordPrice, tt.locale, rand.New(rand.NewSource(7)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !regexp.MustCompile(tt.pattern).MatchString(got) {
+				t.Errorf("Generate() = %q, want match of %s", got, tt.pattern)
+			}
+		})
+	}
+}
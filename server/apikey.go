@@ -0,0 +1,109 @@
+package server
+
+import (
+	"log"
+	"time"
+
+	"github.com/cod3rboy/yaps/apikey"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Constants for api key lookup
+const (
+	headerAPIKey = "X-API-Key"
+	keyAPIKey    = "key"
+	localsAPIKey = "apikey"
+)
+
+// Client Errors for api keys
+var (
+	ErrMissingAPIKey      = fiber.NewError(fiber.StatusUnauthorized, "missing api key ("+headerAPIKey+" header or "+keyAPIKey+" query parameter)")
+	ErrInvalidAPIKey      = fiber.NewError(fiber.StatusUnauthorized, "invalid api key")
+	ErrOriginNotAllowed   = fiber.NewError(fiber.StatusForbidden, "origin not allowed for api key")
+	ErrFormatNotAllowed   = fiber.NewError(fiber.StatusForbidden, "image format not allowed for api key")
+	ErrSizeNotAllowed     = fiber.NewError(fiber.StatusForbidden, "image size not allowed for api key")
+	ErrDailyQuotaExceeded = fiber.NewError(fiber.StatusTooManyRequests, "daily quota exceeded for api key")
+)
+
+// An apiKeyLocal stores the authenticated api key and the usage store of a request.
+type apiKeyLocal struct {
+	key       *apikey.Key
+	store     *apikey.Store
+	charged   bool      // Whether a render is recorded for the request by authorizeRender
+	format    string    // Image format of the recorded render
+	chargedAt time.Time // Time at which the render is recorded
+}
+
+// NewHandlerAPIKey returns a handler which passes on only the requests having an api key from registry.
+//
+// The api key is read from the X-API-Key header or the key query parameter.
+// The origin of the request must be allowed by the key. The format, size and quota limits
+// of the key are checked by the image handlers with [authorizeRender].
+func NewHandlerAPIKey(registry apikey.Registry, store *apikey.Store) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		value := ctx.Get(headerAPIKey, ctx.Query(keyAPIKey))
+		if value == "" {
+			return ErrMissingAPIKey
+		}
+		key, exists := registry.Lookup(value)
+		if !exists {
+			return ErrInvalidAPIKey
+		}
+		if !key.AllowsOrigin(ctx.Get(fiber.HeaderOrigin)) {
+			return ErrOriginNotAllowed
+		}
+		local := &apiKeyLocal{key: key, store: store}
+		ctx.Locals(localsAPIKey, local)
+		err := ctx.Next()
+		// Only images which are generated and sent count towards the quota
+		if local.charged && (err != nil || ctx.Response().StatusCode() >= fiber.StatusBadRequest) {
+			if refundErr := store.Decrement(key, local.format, local.chargedAt); refundErr != nil {
+				log.Printf("failed to refund render of api key %s: %v", key.Name, refundErr)
+			}
+		}
+		return err
+	}
+}
+
+// HandlerUsage is a handler to serve today's usage of the api key of the request as JSON.
+//
+// It responds with 404 status code when api key authentication is disabled.
+func HandlerUsage(ctx *fiber.Ctx) error {
+	local, ok := ctx.Locals(localsAPIKey).(*apiKeyLocal)
+	if !ok {
+		return fiber.ErrNotFound
+	}
+	usage, err := local.store.Usage(local.key.Name, time.Now())
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	return ctx.JSON(usage)
+}
+
+// authorizeRender checks the limits of the api key of the request for rendering an image
+// of given format, width and height, and records the render in the usage store.
+// The render is refunded by the handler of [NewHandlerAPIKey] if the request fails afterwards.
+//
+// It returns nil when api key authentication is disabled.
+func authorizeRender(ctx *fiber.Ctx, format string, width, height int) error {
+	local, ok := ctx.Locals(localsAPIKey).(*apiKeyLocal)
+	if !ok {
+		return nil
+	}
+	if !local.key.AllowsFormat(format) {
+		return ErrFormatNotAllowed
+	}
+	if !local.key.AllowsSize(width, height) {
+		return ErrSizeNotAllowed
+	}
+	now := time.Now()
+	switch err := local.store.Increment(local.key, format, now); err {
+	case nil:
+		local.charged, local.format, local.chargedAt = true, format, now
+		return nil
+	case apikey.ErrQuotaExceeded:
+		return ErrDailyQuotaExceeded
+	default:
+		return fiber.ErrInternalServerError
+	}
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/cod3rboy/yaps/apikey"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestHandlerAPIKey(t *testing.T) {
+	store, err := apikey.OpenStore(filepath.Join(t.TempDir(), "usage.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	registry := apikey.Registry{
+		"k1": {Key: "k1", Name: "team-a"},
+		"k2": {Key: "k2", Name: "team-b", AllowedFormats: []string{"png"}, MaxWidth: 200, MaxHeight: 200, DailyQuota: 2, AllowedOrigins: []string{"https://example.com"}},
+	}
+	router := fiber.New()
+	router.Use(NewHandlerAPIKey(registry, store))
+	router.Get("/usage", HandlerUsage)
+	// Render which fails after it is authorized
+	router.Get("/broken", func(ctx *fiber.Ctx) error {
+		if err := authorizeRender(ctx, "png", 100, 100); err != nil {
+			return err
+		}
+		return fiber.ErrInternalServerError
+	})
+	registerRoutes(router)
+
+	tests := []struct {
+		name               string
+		route              string
+		headers            map[string]string
+		expectedStatusCode int
+	}{
+		{name: "Missing key", route: "/png", expectedStatusCode: 401},
+		{name: "Unknown key", route: "/png?key=k0", expectedStatusCode: 401},
+		{name: "Key in query", route: "/png?key=k1", expectedStatusCode: 200},
+		{name: "Key in header", route: "/jpg", headers: map[string]string{"X-API-Key": "k1"}, expectedStatusCode: 200},
+		{name: "Disallowed origin", route: "/png?key=k2", headers: map[string]string{"Origin": "https://foo.com"}, expectedStatusCode: 403},
+		{name: "Disallowed format", route: "/jpg?key=k2", expectedStatusCode: 403},
+		{name: "Disallowed size", route: "/png?key=k2&s=300", expectedStatusCode: 403},
+		{name: "Disallowed scaled size", route: "/png?key=k2&s=150&x=2", expectedStatusCode: 403},
+		{name: "Failed render", route: "/broken?key=k2", expectedStatusCode: 500},
+		{name: "Failed render again", route: "/broken?key=k2", expectedStatusCode: 500},
+		{name: "First render", route: "/png?key=k2", headers: map[string]string{"Origin": "https://example.com"}, expectedStatusCode: 200},
+		{name: "Second render", route: "/200x100.png?key=k2", expectedStatusCode: 200},
+		{name: "Quota exceeded", route: "/png?key=k2", expectedStatusCode: 429},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.route, nil)
+			for header, value := range tt.headers {
+				req.Header.Set(header, value)
+			}
+			res, err := router.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.expectedStatusCode {
+				t.Errorf("expected status code = %d, actual status code = %d", tt.expectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+
+	res, err := router.Test(httptest.NewRequest(http.MethodGet, "/usage?key=k2", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	usage := new(apikey.Usage)
+	if err := json.NewDecoder(res.Body).Decode(usage); err != nil {
+		t.Fatal(err)
+	}
+	if usage.Name != "team-b" || usage.Total != 2 || usage.Formats["png"] != 2 {
+		t.Errorf("usage = %+v, want name = team-b, total = 2, png = 2", usage)
+	}
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"errors"
+	"hash/fnv"
+	"strings"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/cod3rboy/yaps/utils"
+	"github.com/cod3rboy/yaps/utils/sliceutils"
+	"github.com/cod3rboy/yaps/utils/stringutils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Constants for avatar query parameter keys
+const (
+	keyName  = "name"
+	keyShape = "shape"
+)
+
+// Client Errors for avatars
+var (
+	ErrInvalidParamShape = fiber.NewError(fiber.ErrBadRequest.Code, "invalid shape ("+keyShape+") value")
+)
+
+// Supported avatar shapes
+var avatarShapes = []string{
+	img.SHAPE_CIRCLE,
+	img.SHAPE_ROUNDED,
+	img.SHAPE_SQUARE,
+}
+
+// Default values for avatar parameters
+var (
+	defaultAvatarSize  = img.Size{Width: 128, Height: 128}
+	defaultAvatarShape = img.SHAPE_CIRCLE
+)
+
+// Palette of avatar background colors, can be overridden with [LoadAvatarPalette]
+var avatarPalette = []img.Color{
+	{R: 0xEF, G: 0x44, B: 0x44}, // Red
+	{R: 0xF9, G: 0x73, B: 0x16}, // Orange
+	{R: 0xF5, G: 0x9E, B: 0x0B}, // Amber
+	{R: 0x84, G: 0xCC, B: 0x16}, // Lime
+	{R: 0x22, G: 0xC5, B: 0x5E}, // Green
+	{R: 0x14, G: 0xB8, B: 0xA6}, // Teal
+	{R: 0x06, G: 0xB6, B: 0xD4}, // Cyan
+	{R: 0x3B, G: 0x82, B: 0xF6}, // Blue
+	{R: 0x63, G: 0x66, B: 0xF1}, // Indigo
+	{R: 0x8B, G: 0x5C, B: 0xF6}, // Violet
+	{R: 0xD9, G: 0x46, B: 0xEF}, // Fuchsia
+	{R: 0xEC, G: 0x48, B: 0x99}, // Pink
+	{R: 0x33, G: 0x41, B: 0x55}, // Slate
+}
+
+// LoadAvatarPalette replaces the avatar palette with comma-separated hexadecimal colors in value e.g. EF4444,3B82F6,334155.
+//
+// If an error occurs while parsing any color, the palette is not changed and it returns that error.
+func LoadAvatarPalette(value string) error {
+	palette, err := parsePalette(value)
+	if err != nil {
+		return err
+	}
+	avatarPalette = palette
+	return nil
+}
+
+// parsePalette returns the colors parsed from comma-separated hexadecimal colors in value.
+//
+// If an error occurs while parsing any color or value has no colors, it returns nil, error.
+func parsePalette(value string) ([]img.Color, error) {
+	palette := []img.Color{}
+	for _, colorValue := range strings.Split(value, ",") {
+		colorValue = strings.TrimSpace(colorValue)
+		if colorValue == "" {
+			continue
+		}
+		color, err := parseColor(colorValue)
+		if err != nil {
+			return nil, err
+		}
+		palette = append(palette, *color)
+	}
+	if len(palette) == 0 {
+		return nil, errors.New("palette must have at least one color")
+	}
+	return palette, nil
+}
+
+// paletteColor returns a color from palette picked deterministically by the hash of key.
+//
+// The same key always returns the same color for the same palette.
+func paletteColor(palette []img.Color, key string) img.Color {
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	return palette[hash.Sum32()%uint32(len(palette))]
+}
+
+// HandlerAvatar is a handler to serve avatar generation request with initials of a name.
+//
+// The background color is picked from the avatar palette by the hash of the name and the text color
+// is either white or black, whichever is more readable. Both can be overridden with query parameters.
+func HandlerAvatar(ctx *fiber.Ctx) error {
+	format := ctx.Params(keyFormat)
+	if !sliceutils.ContainsString(SupportedFormats, format) {
+		return ErrUnsupportedFormat
+	}
+	name := strings.TrimSpace(ctx.Query(keyName))
+
+	preset := presets[defaultPresetName]
+	size, err := getParamSize(ctx, defaultAvatarSize, 0)
+	if err != nil {
+		return ErrInvalidParamSize
+	}
+	randomizer, err := newColorRandomizer(ctx)
+	if err != nil {
+		return ErrInvalidParamScheme
+	}
+	bgColor, err := getParamBgColor(ctx, paletteColor(avatarPalette, strings.ToLower(name)), randomizer)
+	if err != nil {
+		return ErrInvalidParamBgColor
+	}
+	minContrast, err := getParamContrast(ctx)
+	if err != nil {
+		return ErrInvalidParamContrast
+	}
+	txtColor, err := getParamTextColor(ctx, nil, bgColor, randomizer, minContrast)
+	if err != nil {
+		return textColorError(err)
+	}
+	scale, err := getParamScale(ctx, preset.Scale)
+	if err != nil {
+		return ErrInvalidParamScale
+	}
+	font, err := getParamFont(ctx, preset.Font)
+	if err != nil {
+		return ErrInvalidParamFont
+	}
+	shape := ctx.Query(keyShape, defaultAvatarShape)
+	if !sliceutils.ContainsString(avatarShapes, shape) {
+		return ErrInvalidParamShape
+	}
+	if err := authorizeRender(ctx, format, utils.ScaleDimension(size.Width, scale), utils.ScaleDimension(size.Height, scale)); err != nil {
+		return err
+	}
+
+	params := &img.AvatarParams{
+		Format:          format,
+		Size:            size,
+		Shape:           shape,
+		BackgroundColor: bgColor,
+		TextColor:       txtColor,
+		Scale:           scale,
+		Initials:        getParamText(ctx, stringutils.Initials(name)),
+		Font:            font,
+	}
+
+	result, err := img.GenerateAvatar(params)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	if !randomizer.cacheable() {
+		ctx.Set(fiber.HeaderCacheControl, "no-store")
+	}
+	ctx.Set(headerContrastRatio, formatContrastRatio(img.ContrastRatio(*bgColor, *txtColor)))
+	return sendResult(ctx, result)
+}
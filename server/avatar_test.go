@@ -0,0 +1,86 @@
+package server
+
+import (
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestParsePalette(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantLen int
+		wantErr bool
+	}{
+		{value: "EF4444,3B82F6,334155", wantLen: 3},
+		{value: " FFF , 000 ,", wantLen: 2},
+		{value: "", wantErr: true},
+		{value: "EF4444,XYZ", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			palette, err := parsePalette(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePalette() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(palette) != tt.wantLen {
+				t.Errorf("parsePalette() len = %d, want %d", len(palette), tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestPaletteColor(t *testing.T) {
+	first, second := paletteColor(avatarPalette, "jane doe"), paletteColor(avatarPalette, "jane doe")
+	if first != second {
+		t.Errorf("expected same color for same key, actual %+v and %+v", first, second)
+	}
+	palette := []img.Color{{R: 1}}
+	if color := paletteColor(palette, "anything"); color != palette[0] {
+		t.Errorf("expected only palette color, actual %+v", color)
+	}
+}
+
+func TestHandlerAvatar(t *testing.T) {
+	router := fiber.New()
+	registerRoutes(router)
+
+	tests := []struct {
+		route              string
+		expectedStatusCode int
+		expectedWidth      int
+		expectedHeight     int
+	}{
+		{route: "/avatar/png?name=Jane+Doe", expectedStatusCode: 200, expectedWidth: 128, expectedHeight: 128},
+		{route: "/avatar/png?name=Jane+Doe&shape=rounded&s=64&x=2", expectedStatusCode: 200, expectedWidth: 128, expectedHeight: 128},
+		{route: "/avatar/png?name=Jane&shape=square&b=000&c=fff", expectedStatusCode: 200, expectedWidth: 128, expectedHeight: 128},
+		{route: "/avatar/jpg?name=Jane+Doe", expectedStatusCode: 200},
+		{route: "/avatar/gif?name=Jane+Doe", expectedStatusCode: 400},
+		{route: "/avatar/png?name=Jane+Doe&shape=star", expectedStatusCode: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			res, err := router.Test(httptest.NewRequest(http.MethodGet, tt.route, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.expectedStatusCode {
+				t.Fatalf("expected status code = %d, actual status code = %d", tt.expectedStatusCode, res.StatusCode)
+			}
+			if tt.expectedWidth == 0 {
+				return
+			}
+			config, err := png.DecodeConfig(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.Width != tt.expectedWidth || config.Height != tt.expectedHeight {
+				t.Errorf("expected size = %dx%d, actual size = %dx%d", tt.expectedWidth, tt.expectedHeight, config.Width, config.Height)
+			}
+		})
+	}
+}
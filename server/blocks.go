@@ -0,0 +1,104 @@
+package server
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/cod3rboy/yaps/utils/sliceutils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Constants for text block query parameter keys, each also the prefix of the style keys of its block
+const (
+	keyTitle    = "title"
+	keySubtitle = "subtitle"
+	keyCaption  = "caption"
+)
+
+// Suffixes of the style query parameter keys of a text block e.g. titlesize
+const (
+	blockKeySize     = "size"
+	blockKeyColor    = "color"
+	blockKeyFont     = "font"
+	blockKeyPosition = "pos"
+	blockKeyLines    = "lines"
+)
+
+// Client Errors for text blocks
+var (
+	ErrInvalidParamBlockSize     = fiber.NewError(fiber.ErrBadRequest.Code, "invalid text block size (titlesize, subtitlesize or captionsize) value")
+	ErrInvalidParamBlockColor    = fiber.NewError(fiber.ErrBadRequest.Code, "invalid text block color (titlecolor, subtitlecolor or captioncolor) value")
+	ErrInvalidParamBlockFont     = fiber.NewError(fiber.ErrBadRequest.Code, "invalid text block font (titlefont, subtitlefont or captionfont) value")
+	ErrInvalidParamBlockPosition = fiber.NewError(fiber.ErrBadRequest.Code, "invalid text block position (titlepos, subtitlepos or captionpos) value")
+	ErrInvalidParamBlockLines    = fiber.NewError(fiber.ErrBadRequest.Code, "invalid text block lines (titlelines, subtitlelines or captionlines) value")
+)
+
+// A textBlockStyle stores the query parameter key of a text block and its default style.
+type textBlockStyle struct {
+	key      string
+	size     float64 // Font size as a percentage of the image height
+	position string
+}
+
+// Styles of the text blocks in drawing order. Blocks at the same position are stacked in this order.
+var textBlockStyles = []textBlockStyle{
+	{key: keyTitle, size: 14, position: img.POSITION_CENTER},
+	{key: keySubtitle, size: 8, position: img.POSITION_CENTER},
+	{key: keyCaption, size: 5, position: img.POSITION_BOTTOM},
+}
+
+// getParamTextBlocks returns the title, subtitle and caption text blocks read from query parameters.
+//
+// The text of a block may be a generator keyword and may contain variables, like the image text.
+// Each block takes its size, color, font, position and maximum number of lines from the query parameters
+// prefixed with its key e.g. titlesize, falling back to the style of the block, the defaultFont and the defaultColor.
+// If no text block is present in query parameters, it returns an empty slice, nil.
+// If a parameter is invalid, it returns nil, client error.
+func getParamTextBlocks(ctx *fiber.Ctx, defaultFont string, defaultColor *img.Color, randomizer *colorRandomizer, variables *textVariables) ([]img.TextBlock, error) {
+	blocks := []img.TextBlock{}
+	for _, style := range textBlockStyles {
+		text := ctx.Query(style.key)
+		if text == "" {
+			continue
+		}
+		text, err := getParamGeneratedText(ctx, text, randomizer)
+		if err != nil {
+			return nil, err
+		}
+		block := img.TextBlock{
+			Text:     variables.expand(text),
+			Size:     style.size,
+			Color:    defaultColor,
+			Font:     ctx.Query(style.key+blockKeyFont, defaultFont),
+			Position: ctx.Query(style.key+blockKeyPosition, style.position),
+		}
+		if sizeValue := ctx.Query(style.key + blockKeySize); sizeValue != "" {
+			size, err := strconv.ParseFloat(sizeValue, 64)
+			if err != nil || math.IsNaN(size) || size <= 0 || size > 100 {
+				return nil, ErrInvalidParamBlockSize
+			}
+			block.Size = size
+		}
+		if colorValue := ctx.Query(style.key + blockKeyColor); colorValue != "" {
+			if block.Color, err = parseColor(colorValue); err != nil {
+				return nil, ErrInvalidParamBlockColor
+			}
+		}
+		if !img.HasFont(block.Font) {
+			return nil, ErrInvalidParamBlockFont
+		}
+		if !sliceutils.ContainsString(overlayPositions, block.Position) {
+			return nil, ErrInvalidParamBlockPosition
+		}
+		if linesValue := ctx.Query(style.key + blockKeyLines); linesValue != "" {
+			lines, err := strconv.Atoi(linesValue)
+			if err != nil || lines < 1 {
+				return nil, ErrInvalidParamBlockLines
+			}
+			block.MaxLines = lines
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
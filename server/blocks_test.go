@@ -0,0 +1,65 @@
+package server
+
+import (
+	"image"
+	"image/color"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestHandlerImageTextBlocks(t *testing.T) {
+	router := fiber.New()
+	registerRoutes(router)
+
+	tests := []struct {
+		route      string
+		statusCode int
+	}{
+		{route: "/1440x600.png?title=Build%20faster&subtitle=Hero%20images&caption={w}x{h}", statusCode: 200},
+		{route: "/png?title=title&subtitle=lorem:12&seed=post-1&locale=de", statusCode: 200},
+		{route: "/png?title=Article&titlepos=top-left&titlesize=10&titlecolor=fff&titlefont=Go-Bold&titlelines=2", statusCode: 200},
+		{route: "/png?caption=5%20min%20read&captionpos=bottom-right&textbox=pill", statusCode: 200},
+		{route: "/png?title=Hello&titlesize=0", statusCode: 400},
+		{route: "/png?title=Hello&titlesize=big", statusCode: 400},
+		{route: "/png?title=hi&titlesize=NaN", statusCode: 400},
+		{route: "/png?caption=hi&captionsize=Inf", statusCode: 400},
+		{route: "/png?subtitle=Hello&subtitlecolor=blue", statusCode: 400},
+		{route: "/png?title=Hello&titlefont=Comic", statusCode: 400},
+		{route: "/png?caption=Hello&captionpos=middle", statusCode: 400},
+		{route: "/png?title=Hello&titlelines=0", statusCode: 400},
+		{route: "/png?title=lorem:0", statusCode: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			res, err := router.Test(httptest.NewRequest(http.MethodGet, tt.route, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.statusCode {
+				t.Errorf("expected status code = %d, actual status code = %d", tt.statusCode, res.StatusCode)
+			}
+		})
+	}
+
+	// Title is drawn in its color at its position
+	red := color.RGBA{R: 0xFF, A: 0xFF}
+	outputs := []struct {
+		route string
+		area  image.Rectangle
+	}{
+		{route: "/400x200.png?b=000&title=Article&titlecolor=f00", area: image.Rect(100, 50, 300, 150)},
+		{route: "/400x200.png?b=000&title=Article&titlecolor=f00&titlepos=top-left", area: image.Rect(0, 0, 200, 100)},
+	}
+	for _, tt := range outputs {
+		decoded := getImage(t, router, tt.route)
+		if size := decoded.Bounds().Size(); size != image.Pt(400, 200) {
+			t.Errorf("route = %s, expected size = 400x200, actual size = %v", tt.route, size)
+		}
+		if title := colorBounds(decoded, red); title.Empty() || !title.In(tt.area) {
+			t.Errorf("route = %s, expected title in %v, actual = %v", tt.route, tt.area, title)
+		}
+	}
+}
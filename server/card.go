@@ -0,0 +1,129 @@
+package server
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/cod3rboy/yaps/utils"
+	"github.com/cod3rboy/yaps/utils/sliceutils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Constants for share card query parameter keys, in addition to title and subtitle
+const (
+	keyPlatform = "platform"
+	keyAuthor   = "author"
+	keyLogo     = "logo"
+	keyTheme    = "theme"
+)
+
+// Client Errors for share cards
+var (
+	ErrInvalidParamTitle     = fiber.NewError(fiber.ErrBadRequest.Code, "invalid title ("+keyTitle+") value")
+	ErrInvalidParamPlatform  = fiber.NewError(fiber.ErrBadRequest.Code, "invalid platform ("+keyPlatform+") value")
+	ErrInvalidParamLogo      = fiber.NewError(fiber.ErrBadRequest.Code, "invalid logo ("+keyLogo+") value")
+	ErrInvalidParamTheme     = fiber.NewError(fiber.ErrBadRequest.Code, "invalid theme ("+keyTheme+") value")
+	ErrInvalidParamTitleFont = fiber.NewError(fiber.ErrBadRequest.Code, "invalid title font ("+keyTitle+blockKeyFont+") value")
+)
+
+// Mapping of a share card platform to the size alias of its cards
+var cardPlatforms = map[string]string{
+	"og":       "og",
+	"twitter":  "twitter-card",
+	"linkedin": "linkedin-post",
+}
+
+// Themes supported by the theme parameter
+var cardThemes = []string{img.CARD_THEME_LIGHT, img.CARD_THEME_DARK, img.CARD_THEME_GRADIENT}
+
+// Default values for share card parameters
+var (
+	defaultCardPlatform = "og"
+	defaultCardTheme    = img.CARD_THEME_LIGHT
+)
+
+// HandlerCard is a handler to serve share card generation request e.g. Open Graph images of blog posts.
+//
+// The card size is picked by the platform, and the title, subtitle and author are laid out on the theme background
+// with the logo asset at the top. Like text blocks, the texts may be generator keywords and may contain variables.
+func HandlerCard(ctx *fiber.Ctx) error {
+	format := ctx.Params(keyFormat)
+	if !sliceutils.ContainsString(SupportedFormats, format) {
+		return ErrUnsupportedFormat
+	}
+	alias, exists := cardPlatforms[strings.ToLower(ctx.Query(keyPlatform, defaultCardPlatform))]
+	if !exists {
+		return ErrInvalidParamPlatform
+	}
+	size, _ := lookupSizeAlias(alias)
+	theme := ctx.Query(keyTheme, defaultCardTheme)
+	if !sliceutils.ContainsString(cardThemes, theme) {
+		return ErrInvalidParamTheme
+	}
+	logo := ctx.Query(keyLogo)
+	if logo != "" && !img.HasAsset(logo) {
+		return ErrInvalidParamLogo
+	}
+
+	preset := presets[defaultPresetName]
+	scale, err := getParamScale(ctx, preset.Scale)
+	if err != nil || scale <= 0 {
+		return ErrInvalidParamScale
+	}
+	font, err := getParamFont(ctx, preset.Font)
+	if err != nil {
+		return ErrInvalidParamFont
+	}
+	titleFont := ctx.Query(keyTitle+blockKeyFont, img.DefaultCardTitleFont)
+	if !img.HasFont(titleFont) {
+		return ErrInvalidParamTitleFont
+	}
+	randomizer, err := newColorRandomizer(ctx)
+	if err != nil {
+		return ErrInvalidParamScheme
+	}
+	width, height := utils.ScaleDimension(size.Width, scale), utils.ScaleDimension(size.Height, scale)
+	variables := &textVariables{
+		width:  width,
+		height: height,
+		format: format,
+		scale:  scale,
+		seed:   ctx.Query(keySeed),
+		now:    time.Now(),
+	}
+	texts := map[string]string{}
+	for _, key := range []string{keyTitle, keySubtitle, keyAuthor} {
+		text, err := getParamGeneratedText(ctx, strings.TrimSpace(ctx.Query(key)), randomizer)
+		if err != nil {
+			return err
+		}
+		texts[key] = variables.expand(text)
+	}
+	if texts[keyTitle] == "" {
+		return ErrInvalidParamTitle
+	}
+	if err := authorizeRender(ctx, format, width, height); err != nil {
+		return err
+	}
+
+	result, err := img.GenerateCard(&img.CardParams{
+		Format:    format,
+		Size:      size,
+		Scale:     scale,
+		Theme:     theme,
+		Title:     texts[keyTitle],
+		Subtitle:  texts[keySubtitle],
+		Author:    texts[keyAuthor],
+		Logo:      logo,
+		TitleFont: titleFont,
+		Font:      font,
+	})
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	if !randomizer.cacheable() || !variables.cacheable() {
+		ctx.Set(fiber.HeaderCacheControl, "no-store")
+	}
+	return sendResult(ctx, result)
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestHandlerCard(t *testing.T) {
+	dir := t.TempDir()
+	buffer := new(bytes.Buffer)
+	if err := png.Encode(buffer, image.NewNRGBA(image.Rect(0, 0, 40, 10))); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "brand.png"), buffer.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := img.LoadAssets(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	router := fiber.New()
+	registerRoutes(router)
+
+	tests := []struct {
+		route      string
+		statusCode int
+		width      int
+		height     int
+	}{
+		{route: "/card/png?title=Hello", statusCode: 200, width: 1200, height: 630},
+		{route: "/card/png?title=Hello&platform=twitter&x=0.5", statusCode: 200, width: 600, height: 314},
+		{route: "/card/png?title=Hello&platform=LinkedIn", statusCode: 200, width: 1200, height: 627},
+		{route: "/card/jpg?title=Release%20notes&subtitle=Version%202&author=Docs&logo=brand&theme=dark", statusCode: 200},
+		{route: "/card/png?title=lorem:20&theme=gradient&titlefont=Go-Regular&f=Go-Italic", statusCode: 200},
+		{route: "/card/gif?title=Hello", statusCode: 400},
+		{route: "/card/png", statusCode: 400},
+		{route: "/card/png?title=Hello&platform=myspace", statusCode: 400},
+		{route: "/card/png?title=Hello&theme=sepia", statusCode: 400},
+		{route: "/card/png?title=Hello&logo=missing", statusCode: 400},
+		{route: "/card/png?title=Hello&titlefont=missing", statusCode: 400},
+		{route: "/card/png?title=Hello&x=-1", statusCode: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			res, err := router.Test(httptest.NewRequest(http.MethodGet, tt.route, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.statusCode {
+				t.Fatalf("expected status code = %d, actual status code = %d", tt.statusCode, res.StatusCode)
+			}
+			if tt.width == 0 {
+				return
+			}
+			config, err := png.DecodeConfig(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.Width != tt.width || config.Height != tt.height {
+				t.Errorf("expected size = %dx%d, actual size = %dx%d", tt.width, tt.height, config.Width, config.Height)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Constant for contrast level query parameter key
+const keyContrast = "contrast"
+
+// Value of text color parameter which computes a readable color from the background color
+const colorAuto = "auto"
+
+// Response header which carries the contrast ratio between text and background colors
+const headerContrastRatio = "X-Contrast-Ratio"
+
+// Constants for WCAG contrast level names
+const (
+	contrastLevelAA  = "aa"
+	contrastLevelAAA = "aaa"
+)
+
+// Mapping of a WCAG contrast level to its minimum contrast ratio for normal text
+var contrastLevels = map[string]float64{
+	contrastLevelAA:  4.5,
+	contrastLevelAAA: 7.0,
+}
+
+// Client Errors for contrast level
+var (
+	ErrInvalidParamContrast = fiber.NewError(fiber.ErrBadRequest.Code, "invalid contrast level ("+keyContrast+") value")
+	ErrUnreachableContrast  = fiber.NewError(fiber.ErrBadRequest.Code, "no text color meets the contrast level ("+keyContrast+") against the background color")
+)
+
+// Default contrast level of automatic text color, can be overridden with [LoadContrastLevel]
+var defaultContrastLevel = contrastLevelAA
+
+// LoadContrastLevel sets the default contrast level of automatic text color to value, either AA or AAA.
+//
+// If value is not a contrast level, the default is not changed and it returns an error.
+func LoadContrastLevel(value string) error {
+	level := strings.ToLower(value)
+	if _, exists := contrastLevels[level]; !exists {
+		return errors.New("contrast level must be either AA or AAA")
+	}
+	defaultContrastLevel = level
+	return nil
+}
+
+// getParamContrast returns the minimum contrast ratio of the contrast level read from query parameters.
+//
+// If the contrast level does not exist, it returns 0.0, error.
+// If no contrast level is present in query parameters, it returns the ratio of defaultContrastLevel.
+func getParamContrast(ctx *fiber.Ctx) (float64, error) {
+	level := strings.ToLower(ctx.Query(keyContrast, defaultContrastLevel))
+	ratio, exists := contrastLevels[level]
+	if !exists {
+		return 0.0, errors.New("contrast level not found with name " + level)
+	}
+	return ratio, nil
+}
+
+// textColorError returns the client error for an error which occurred while reading the text color, see [getParamTextColor].
+func textColorError(err error) error {
+	if errors.Is(err, img.ErrContrastRatio) {
+		return ErrUnreachableContrast
+	}
+	return ErrInvalidParamTextColor
+}
+
+// formatContrastRatio returns the contrast ratio formatted with two decimal places e.g. 4.58.
+func formatContrastRatio(ratio float64) string {
+	return strconv.FormatFloat(ratio, 'f', 2, 64)
+}
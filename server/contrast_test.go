@@ -0,0 +1,74 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestLoadContrastLevel(t *testing.T) {
+	defer func() { defaultContrastLevel = contrastLevelAA }()
+
+	if err := LoadContrastLevel("AAA"); err != nil {
+		t.Fatal(err)
+	}
+	if defaultContrastLevel != contrastLevelAAA {
+		t.Errorf("expected default contrast level = %s, actual = %s", contrastLevelAAA, defaultContrastLevel)
+	}
+	if err := LoadContrastLevel("A"); err == nil {
+		t.Errorf("expected error for unknown contrast level")
+	}
+	if defaultContrastLevel != contrastLevelAAA {
+		t.Errorf("expected default contrast level to be unchanged on error")
+	}
+}
+
+func TestHandlerImageAutoTextColor(t *testing.T) {
+	router := fiber.New()
+	registerRoutes(router)
+
+	tests := []struct {
+		route    string
+		minRatio float64
+	}{
+		{route: "/png?b=1e3a8a&c=auto", minRatio: 4.5},
+		{route: "/png?b=808080&c=auto", minRatio: 4.5},
+		{route: "/png?b=1e3a8a&c=auto&contrast=aaa", minRatio: 7.0},
+		{route: "/png?b=ff0000&c=auto&contrast=AA", minRatio: 4.5},
+		{route: "/png?b=1e3a8a&c=random&seed=7&contrast=aaa", minRatio: 7.0},
+		{route: "/avatar/png?name=Jane+Doe&b=808080", minRatio: 4.5},
+		{route: "/avatar/png?name=Jane+Doe&b=1e3a8a&c=auto&contrast=aaa", minRatio: 7.0},
+		{route: "/identicon/png?seed=jane&b=1e3a8a&c=auto&contrast=aaa", minRatio: 7.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			res, err := router.Test(httptest.NewRequest(http.MethodGet, tt.route, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != 200 {
+				t.Fatalf("expected status code = 200, actual status code = %d", res.StatusCode)
+			}
+			ratio, err := strconv.ParseFloat(res.Header.Get(headerContrastRatio), 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ratio < tt.minRatio {
+				t.Errorf("expected contrast ratio >= %.1f, actual = %.2f", tt.minRatio, ratio)
+			}
+		})
+	}
+
+	for _, route := range []string{"/png?c=auto&contrast=a", "/avatar/png?name=Jane&contrast=a", "/identicon/png?seed=jane&contrast=a", "/png?b=808080&c=auto&contrast=aaa", "/avatar/png?name=Jane&b=808080&contrast=aaa"} {
+		res, err := router.Test(httptest.NewRequest(http.MethodGet, route, nil), -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != 400 {
+			t.Errorf("expected status code = 400 for contrast level of %s, actual status code = %d", route, res.StatusCode)
+		}
+	}
+}
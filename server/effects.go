@@ -0,0 +1,111 @@
+package server
+
+import (
+	"github.com/cod3rboy/yaps/img"
+	"github.com/cod3rboy/yaps/utils/sliceutils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Constants for text effects query parameter keys
+const (
+	keyStroke           = "stroke"
+	keyStrokeColor      = "strokecolor"
+	keyTextShadow       = "textshadow"
+	keyTextShadowBlur   = "textshadowblur"
+	keyTextShadowOffset = "textshadowoffset"
+	keyTextBox          = "textbox"
+	keyTextBoxColor     = "textboxcolor"
+	keyTextBoxPadding   = "textboxpadding"
+)
+
+// Client Errors for text effects
+var (
+	ErrInvalidParamStroke           = fiber.NewError(fiber.ErrBadRequest.Code, "invalid stroke ("+keyStroke+") value")
+	ErrInvalidParamStrokeColor      = fiber.NewError(fiber.ErrBadRequest.Code, "invalid stroke color ("+keyStrokeColor+") value")
+	ErrInvalidParamTextShadow       = fiber.NewError(fiber.ErrBadRequest.Code, "invalid text shadow ("+keyTextShadow+") value")
+	ErrInvalidParamTextShadowBlur   = fiber.NewError(fiber.ErrBadRequest.Code, "invalid text shadow blur ("+keyTextShadowBlur+") value")
+	ErrInvalidParamTextShadowOffset = fiber.NewError(fiber.ErrBadRequest.Code, "invalid text shadow offset ("+keyTextShadowOffset+") value")
+	ErrInvalidParamTextBox          = fiber.NewError(fiber.ErrBadRequest.Code, "invalid text box ("+keyTextBox+") value")
+	ErrInvalidParamTextBoxColor     = fiber.NewError(fiber.ErrBadRequest.Code, "invalid text box color ("+keyTextBoxColor+") value")
+	ErrInvalidParamTextBoxPadding   = fiber.NewError(fiber.ErrBadRequest.Code, "invalid text box padding ("+keyTextBoxPadding+") value")
+)
+
+// Text box kinds supported by the text effects parameters
+var textBoxes = []string{img.TEXT_BOX_RECT, img.TEXT_BOX_PILL}
+
+// Default values for text effects parameters
+var (
+	defaultTextShadowBlur   = 4.0
+	defaultTextShadowOffset = 2.0
+	defaultTextBoxPadding   = 8.0
+)
+
+// Largest text shadow blur and text box padding in pixels, before scaling
+const (
+	maxTextShadowBlur = 100.0
+	maxTextBoxPadding = 200.0
+)
+
+// getParamTextEffects returns the text effects read from query parameters.
+//
+// The stroke and box colors default to black or white, whichever is readable against txtColor.
+// The text shadow parameter is the shadow color.
+// If none of stroke, text shadow and text box is present in query parameters, it returns nil, nil.
+// Lengths must be finite and at most [maxLengthParam], the blur at most [maxTextShadowBlur] and the padding
+// at most [maxTextBoxPadding].
+// If a parameter is invalid, it returns nil, client error.
+func getParamTextEffects(ctx *fiber.Ctx, txtColor *img.Color) (*img.TextEffects, error) {
+	if ctx.Query(keyStroke) == "" && ctx.Query(keyTextShadow) == "" && ctx.Query(keyTextBox) == "" {
+		return nil, nil
+	}
+	contrastColor := img.ReadableTextColor(*txtColor)
+
+	strokeWidth, err := parseLengthParam(ctx.Query(keyStroke), 0)
+	if err != nil {
+		return nil, ErrInvalidParamStroke
+	}
+	strokeColor := &contrastColor
+	if strokeColorValue := ctx.Query(keyStrokeColor); strokeColorValue != "" {
+		if strokeColor, err = parseColor(strokeColorValue); err != nil {
+			return nil, ErrInvalidParamStrokeColor
+		}
+	}
+	var shadowColor *img.Color
+	if shadowValue := ctx.Query(keyTextShadow); shadowValue != "" {
+		if shadowColor, err = parseColor(shadowValue); err != nil {
+			return nil, ErrInvalidParamTextShadow
+		}
+	}
+	shadowBlur, err := parseLengthParam(ctx.Query(keyTextShadowBlur), defaultTextShadowBlur)
+	if err != nil || shadowBlur > maxTextShadowBlur {
+		return nil, ErrInvalidParamTextShadowBlur
+	}
+	shadowOffset, err := parseLengthParam(ctx.Query(keyTextShadowOffset), defaultTextShadowOffset)
+	if err != nil {
+		return nil, ErrInvalidParamTextShadowOffset
+	}
+	box := ctx.Query(keyTextBox)
+	if box != "" && !sliceutils.ContainsString(textBoxes, box) {
+		return nil, ErrInvalidParamTextBox
+	}
+	boxColor := &contrastColor
+	if boxColorValue := ctx.Query(keyTextBoxColor); boxColorValue != "" {
+		if boxColor, err = parseColor(boxColorValue); err != nil {
+			return nil, ErrInvalidParamTextBoxColor
+		}
+	}
+	boxPadding, err := parseLengthParam(ctx.Query(keyTextBoxPadding), defaultTextBoxPadding)
+	if err != nil || boxPadding > maxTextBoxPadding {
+		return nil, ErrInvalidParamTextBoxPadding
+	}
+	return &img.TextEffects{
+		StrokeWidth:  strokeWidth,
+		StrokeColor:  strokeColor,
+		ShadowColor:  shadowColor,
+		ShadowBlur:   shadowBlur,
+		ShadowOffset: shadowOffset,
+		Box:          box,
+		BoxColor:     boxColor,
+		BoxPadding:   boxPadding,
+	}, nil
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"image"
+	"image/color"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestHandlerImageTextEffects(t *testing.T) {
+	router := fiber.New()
+	registerRoutes(router)
+
+	tests := []struct {
+		route      string
+		statusCode int
+	}{
+		{route: "/300x200.png?stroke=2", statusCode: 200},
+		{route: "/png?stroke=1.5&strokecolor=000", statusCode: 200},
+		{route: "/png?textshadow=000000&textshadowblur=6&textshadowoffset=3", statusCode: 200},
+		{route: "/png?textbox=pill&textboxcolor=1e293b&textboxpadding=4&c=fff", statusCode: 200},
+		{route: "/png?textbox=box&rotate=diagonal&shape=circle", statusCode: 200},
+		{route: "/png?stroke=-1", statusCode: 400},
+		{route: "/png?stroke=2&strokecolor=red", statusCode: 400},
+		{route: "/png?textshadow=soft", statusCode: 400},
+		{route: "/png?textshadow=000&textshadowblur=x", statusCode: 400},
+		{route: "/png?textbox=circle", statusCode: 400},
+		{route: "/png?textbox=box&textboxpadding=-2", statusCode: 400},
+		{route: "/png?textbox=pill&textboxpadding=NaN", statusCode: 400},
+		{route: "/png?textbox=pill&textboxpadding=Inf", statusCode: 400},
+		{route: "/png?textbox=box&textboxpadding=201", statusCode: 400},
+		{route: "/png?textshadow=000&textshadowblur=1e9", statusCode: 400},
+		{route: "/png?textshadow=000&textshadowoffset=NaN", statusCode: 400},
+		{route: "/png?stroke=Inf", statusCode: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			res, err := router.Test(httptest.NewRequest(http.MethodGet, tt.route, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.statusCode {
+				t.Errorf("expected status code = %d, actual status code = %d", tt.statusCode, res.StatusCode)
+			}
+		})
+	}
+
+	// Outline and box surround the white text, and the shadow is the text moved by the offset
+	white, red := color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}, color.RGBA{R: 0xFF, A: 0xFF}
+	route := "/300x200.png?t=Hi&b=000&c=fff"
+	text := colorBounds(getImage(t, router, route), white)
+	if text.Empty() {
+		t.Fatal("expected white text")
+	}
+	for _, query := range []string{"&stroke=3&strokecolor=f00", "&textbox=box&textboxcolor=f00"} {
+		if effect := colorBounds(getImage(t, router, route+query), red); !text.In(effect) || effect == text {
+			t.Errorf("query = %s, expected red effect around text %v, actual = %v", query, text, effect)
+		}
+	}
+	// Shadow color is drawn with half opacity over the black background
+	shadow := colorBounds(getImage(t, router, route+"&textshadow=f00&textshadowblur=0&textshadowoffset=6"), color.RGBA{R: 0x80, A: 0xFF})
+	if shadow != text.Add(image.Pt(6, 6)) {
+		t.Errorf("expected shadow = %v, actual = %v", text.Add(image.Pt(6, 6)), shadow)
+	}
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/cod3rboy/yaps/utils/sliceutils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Constants for frame query parameter keys
+const (
+	keyBorder       = "border"
+	keyBorderColor  = "bordercolor"
+	keyBorderStyle  = "borderstyle"
+	keyRadius       = "radius"
+	keyShadow       = "shadow"
+	keyShadowColor  = "shadowcolor"
+	keyShadowBlur   = "shadowblur"
+	keyShadowOffset = "shadowoffset"
+)
+
+// Client Errors for frames
+var (
+	ErrInvalidParamBorder       = fiber.NewError(fiber.ErrBadRequest.Code, "invalid border ("+keyBorder+") value")
+	ErrInvalidParamBorderColor  = fiber.NewError(fiber.ErrBadRequest.Code, "invalid border color ("+keyBorderColor+") value")
+	ErrInvalidParamBorderStyle  = fiber.NewError(fiber.ErrBadRequest.Code, "invalid border style ("+keyBorderStyle+") value")
+	ErrInvalidParamRadius       = fiber.NewError(fiber.ErrBadRequest.Code, "invalid radius ("+keyRadius+") value")
+	ErrInvalidParamShadow       = fiber.NewError(fiber.ErrBadRequest.Code, "invalid shadow ("+keyShadow+") value")
+	ErrInvalidParamShadowColor  = fiber.NewError(fiber.ErrBadRequest.Code, "invalid shadow color ("+keyShadowColor+") value")
+	ErrInvalidParamShadowBlur   = fiber.NewError(fiber.ErrBadRequest.Code, "invalid shadow blur ("+keyShadowBlur+") value")
+	ErrInvalidParamShadowOffset = fiber.NewError(fiber.ErrBadRequest.Code, "invalid shadow offset ("+keyShadowOffset+") value")
+)
+
+// Client errors of the frame parameters named by the errors of [img.FrameParams.Validate]
+var frameValidationErrors = map[error]*fiber.Error{
+	img.ErrFrameBorderWidth:  ErrInvalidParamBorder,
+	img.ErrFrameBorderStyle:  ErrInvalidParamBorderStyle,
+	img.ErrFrameRadius:       ErrInvalidParamRadius,
+	img.ErrFrameShadow:       ErrInvalidParamShadow,
+	img.ErrFrameShadowBlur:   ErrInvalidParamShadowBlur,
+	img.ErrFrameShadowOffset: ErrInvalidParamShadowOffset,
+}
+
+// Largest length in pixels accepted by length parameters, before scaling
+const maxLengthParam = 1000.0
+
+// Border styles and shadows supported by the frame parameters
+var (
+	borderStyles = []string{img.BORDER_SOLID, img.BORDER_DASHED}
+	shadows      = []string{img.SHADOW_OUTER, img.SHADOW_INSET}
+)
+
+// Default values for frame parameters
+var (
+	defaultBorderStyle  = img.BORDER_SOLID
+	defaultShadowColor  = img.Black
+	defaultShadowBlur   = 8.0
+	defaultShadowOffset = 4.0
+)
+
+// getParamFrame returns the frame read from query parameters. The border color defaults to defaultBorderColor.
+//
+// If none of border, radius and shadow is present in query parameters, it returns nil, nil.
+// If a parameter is invalid, it returns nil, client error.
+func getParamFrame(ctx *fiber.Ctx, defaultBorderColor *img.Color) (*img.FrameParams, error) {
+	if ctx.Query(keyBorder) == "" && ctx.Query(keyRadius) == "" && ctx.Query(keyShadow) == "" {
+		return nil, nil
+	}
+	borderWidth, err := parseLengthParam(ctx.Query(keyBorder), 0)
+	if err != nil {
+		return nil, ErrInvalidParamBorder
+	}
+	borderColor := defaultBorderColor
+	if borderColorValue := ctx.Query(keyBorderColor); borderColorValue != "" {
+		if borderColor, err = parseColor(borderColorValue); err != nil {
+			return nil, ErrInvalidParamBorderColor
+		}
+	}
+	borderStyle := ctx.Query(keyBorderStyle, defaultBorderStyle)
+	if !sliceutils.ContainsString(borderStyles, borderStyle) {
+		return nil, ErrInvalidParamBorderStyle
+	}
+	radius, err := parseLengthParam(ctx.Query(keyRadius), 0)
+	if err != nil {
+		return nil, ErrInvalidParamRadius
+	}
+	shadow := ctx.Query(keyShadow)
+	if shadow != "" && !sliceutils.ContainsString(shadows, shadow) {
+		return nil, ErrInvalidParamShadow
+	}
+	shadowColor := &img.Color{R: defaultShadowColor.R, G: defaultShadowColor.G, B: defaultShadowColor.B}
+	if shadowColorValue := ctx.Query(keyShadowColor); shadowColorValue != "" {
+		if shadowColor, err = parseColor(shadowColorValue); err != nil {
+			return nil, ErrInvalidParamShadowColor
+		}
+	}
+	shadowBlur, err := parseLengthParam(ctx.Query(keyShadowBlur), defaultShadowBlur)
+	if err != nil {
+		return nil, ErrInvalidParamShadowBlur
+	}
+	shadowOffset, err := parseLengthParam(ctx.Query(keyShadowOffset), defaultShadowOffset)
+	if err != nil {
+		return nil, ErrInvalidParamShadowOffset
+	}
+	return &img.FrameParams{
+		BorderWidth:  borderWidth,
+		BorderColor:  borderColor,
+		BorderStyle:  borderStyle,
+		Radius:       radius,
+		Shadow:       shadow,
+		ShadowColor:  shadowColor,
+		ShadowBlur:   shadowBlur,
+		ShadowOffset: shadowOffset,
+	}, nil
+}
+
+// validateFrame returns the client error of the frame parameter which is out of range for an image of given size
+// scaled by scale, e.g. a shadow offset which does not leave room for the image. If the frame is valid, it returns nil.
+func validateFrame(frame *img.FrameParams, size *img.Size, scale float64) error {
+	err := frame.Validate(size.Width, size.Height, scale)
+	if err == nil {
+		return nil
+	}
+	if clientErr, exists := frameValidationErrors[err]; exists {
+		return clientErr
+	}
+	return fiber.NewError(fiber.ErrBadRequest.Code, err.Error())
+}
+
+// parseLengthParam parses the value as a length in pixels in range [0, [maxLengthParam]].
+//
+// If the value is empty, it returns defaultValue.
+// If the value is not a finite number in range, it returns 0.0, error.
+func parseLengthParam(value string, defaultValue float64) (float64, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	length, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0.0, err
+	}
+	if math.IsNaN(length) || math.IsInf(length, 0) || length < 0 || length > maxLengthParam {
+		return 0.0, errors.New("length must be finite and in range")
+	}
+	return length, nil
+}
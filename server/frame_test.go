@@ -0,0 +1,107 @@
+package server
+
+import (
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestHandlerImageFrame(t *testing.T) {
+	router := fiber.New()
+	registerRoutes(router)
+
+	tests := []struct {
+		route      string
+		statusCode int
+	}{
+		{route: "/png?radius=12", statusCode: 200},
+		{route: "/jpg?radius=12&border=2&bordercolor=f00&borderstyle=dashed", statusCode: 200},
+		{route: "/300x200.png?shadow=outer&shadowblur=10&shadowoffset=2&shadowcolor=333", statusCode: 200},
+		{route: "/png?shadow=inset&x=2", statusCode: 200},
+		{route: "/png?border=-1", statusCode: 400},
+		{route: "/png?border=2&bordercolor=red", statusCode: 400},
+		{route: "/png?border=2&borderstyle=dotted", statusCode: 400},
+		{route: "/png?radius=big", statusCode: 400},
+		{route: "/png?shadow=drop", statusCode: 400},
+		{route: "/png?shadow=outer&shadowcolor=12", statusCode: 400},
+		{route: "/png?shadow=outer&shadowblur=60", statusCode: 400},
+		{route: "/png?shadow=outer&shadowoffset=-2", statusCode: 400},
+		{route: "/png?shadow=outer&shadowblur=NaN", statusCode: 400},
+		{route: "/png?radius=NaN&shape=circle", statusCode: 400},
+		{route: "/png?shadow=inset&shadowoffset=Inf", statusCode: 400},
+		{route: "/png?border=-Inf", statusCode: 400},
+		{route: "/png?shadow=inset&shadowblur=1001", statusCode: 400},
+		{route: "/png?s=100&x=0.5&shadow=outer&shadowblur=40&shadowoffset=19", statusCode: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			res, err := router.Test(httptest.NewRequest(http.MethodGet, tt.route, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.statusCode {
+				t.Errorf("expected status code = %d, actual status code = %d", tt.statusCode, res.StatusCode)
+			}
+		})
+	}
+
+	// Error names the parameter which does not fit
+	res, err := router.Test(httptest.NewRequest(http.MethodGet, "/png?shadow=outer&shadowoffset=90", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body, _ := io.ReadAll(res.Body); string(body) != ErrInvalidParamShadowOffset.Message {
+		t.Errorf("expected error = %q, actual error = %q", ErrInvalidParamShadowOffset.Message, body)
+	}
+
+	res, err = router.Test(httptest.NewRequest(http.MethodGet, "/png?radius=20", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := png.Decode(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, a := decoded.At(0, 0).RGBA(); a != 0 {
+		t.Errorf("expected transparent corner, actual alpha = %d", a>>8)
+	}
+
+	// Matte color fills the corners of JPEG images
+	res, err = router.Test(httptest.NewRequest(http.MethodGet, "/jpg?radius=20&matte=ff0000", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err = jpeg.Decode(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, g, b, _ := decoded.At(0, 0).RGBA(); r>>8 < 0xE0 || g>>8 > 0x20 || b>>8 > 0x20 {
+		t.Errorf("expected red corner, actual color = %d %d %d", r>>8, g>>8, b>>8)
+	}
+
+	// Border is drawn along the edges, around the background
+	red, white := color.RGBA{R: 0xFF, A: 0xFF}, color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+	decoded = getImage(t, router, "/300x200.png?b=f00&border=4&bordercolor=fff")
+	if border := color.RGBAModel.Convert(decoded.At(1, 100)); border != white {
+		t.Errorf("expected white border, actual color = %v", border)
+	}
+	if bounds := colorBounds(decoded, red); bounds != image.Rect(4, 4, 296, 196) {
+		t.Errorf("expected background inside the border = (4,4)-(296,196), actual = %v", bounds)
+	}
+
+	// Card leaves space for the blur and offset of the outer shadow
+	decoded = getImage(t, router, "/300x200.png?b=f00&shadow=outer&shadowblur=10&shadowoffset=2")
+	if size := decoded.Bounds().Size(); size != image.Pt(300, 200) {
+		t.Errorf("expected size = 300x200, actual size = %v", size)
+	}
+	if bounds := colorBounds(decoded, red); bounds != image.Rect(10, 10, 288, 188) {
+		t.Errorf("expected card = (10,10)-(288,188), actual = %v", bounds)
+	}
+}
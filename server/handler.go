@@ -1,9 +1,12 @@
 package server
 
 import (
-	"fmt"
+	"errors"
+	"math"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cod3rboy/yaps/img"
 	"github.com/cod3rboy/yaps/utils"
@@ -28,8 +31,18 @@ const (
 	keyTextColor = "c"
 	keyText      = "t"
 	keyScale     = "x"
+	keyFont      = "f"
+	keyRatio     = "ar"
+	keyPreset    = "preset"
+	keyTextAlias = "text" // Text key used by other placeholder services
 )
 
+// Constant for route parameter key of image format
+const keyFormat = "format"
+
+// Pattern of the size accepted in path-style routes e.g. 300 or 300x200
+var pathSizePattern = regexp.MustCompile(`(?i)^[0-9]+(x[0-9]+)?$`)
+
 // Client Errors
 var (
 	ErrUnsupportedFormat     = fiber.NewError(fiber.ErrBadRequest.Code, "unsupported image format")
@@ -37,6 +50,9 @@ var (
 	ErrInvalidParamScale     = fiber.NewError(fiber.ErrBadRequest.Code, "invalid scale ("+keyScale+") value")
 	ErrInvalidParamBgColor   = fiber.NewError(fiber.ErrBadRequest.Code, "invalid background color ("+keyBgColor+") value")
 	ErrInvalidParamTextColor = fiber.NewError(fiber.ErrBadRequest.Code, "invalid text color ("+keyTextColor+") value")
+	ErrInvalidParamRatio     = fiber.NewError(fiber.ErrBadRequest.Code, "invalid aspect ratio ("+keyRatio+") value")
+	ErrInvalidParamFont      = fiber.NewError(fiber.ErrBadRequest.Code, "invalid font ("+keyFont+") value")
+	ErrInvalidParamPreset    = fiber.NewError(fiber.ErrBadRequest.Code, "invalid preset ("+keyPreset+") value")
 )
 
 // Constants to help parse size parameter
@@ -44,86 +60,331 @@ const (
 	dimensionDelimiter = "x"
 	widthIndex         = 0
 	heightIndex        = 1
+	autoDimension      = "auto" // Dimension computed from the aspect ratio
 )
 
-// Default values for parameters
-var (
-	defaultSize    = img.Size{Width: 100, Height: 100}
-	defaultBgColor = img.Color{
-		// Light Gray (#cccccc)
-		R: 0xCC,
-		G: 0xCC,
-		B: 0xCC,
-	}
-	defaultTextColor = img.Color{
-		// Dark Gray (#969696)
-		R: 0x96,
-		G: 0x96,
-		B: 0x96,
-	}
-	defaultScale = 1.0
-)
+// Delimiters of aspect ratio terms e.g. 16:9 or 16/9
+const ratioDelimiters = ":/"
+
+// Default format for routes without format parameter.
+//
+// Default values of other parameters come from the default preset, see [Preset].
+var defaultFormat = img.IMAGE_PNG
 
 // HandlerImage is a handler to serve image generation request.
 //
 // It is only compatible with [fiber.Handler] inteface and not with [http.Handler] interface.
 func HandlerImage(ctx *fiber.Ctx) error {
-	format := ctx.Params("format", "")
+	format := ctx.Params(keyFormat, defaultFormat)
 	if !sliceutils.ContainsString(SupportedFormats, format) {
 		return ErrUnsupportedFormat
 	}
-	// Get all image parameters from query string
-	size, err := getParamSize(ctx)
+	params, randomizer, variables, err := getParamImage(ctx, format)
+	if err != nil {
+		return err
+	}
+	width, height := utils.ScaleDimension(params.Width, params.Scale), utils.ScaleDimension(params.Height, params.Scale)
+	if err := authorizeRender(ctx, format, width, height); err != nil {
+		return err
+	}
+
+	result, err := img.Generate(params)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	if !randomizer.cacheable() || !variables.cacheable() {
+		ctx.Set(fiber.HeaderCacheControl, "no-store")
+	}
+	ctx.Set(headerContrastRatio, formatContrastRatio(img.ContrastRatio(*params.BackgroundColor, *params.TextColor)))
+	return sendResult(ctx, result)
+}
+
+// getParamImage returns the parameters of an image in given format read from query parameters,
+// falling back to the preset values, along with the randomizer which picked random colors and text
+// and the variables expanded in the text.
+//
+// If a parameter is invalid, it returns nil, nil, nil, client error.
+func getParamImage(ctx *fiber.Ctx, format string) (*img.ImageParams, *colorRandomizer, *textVariables, error) {
+	preset, err := getParamPreset(ctx)
+	if err != nil {
+		return nil, nil, nil, ErrInvalidParamPreset
+	}
+	ratio, err := getParamRatio(ctx)
+	if err != nil {
+		return nil, nil, nil, ErrInvalidParamRatio
+	}
+	size, err := getParamSize(ctx, preset.Size, ratio)
 	if err != nil {
-		return ErrInvalidParamSize
+		return nil, nil, nil, ErrInvalidParamSize
 	}
-	bgColor, err := getParamBgColor(ctx)
+	randomizer, err := newColorRandomizer(ctx)
 	if err != nil {
-		return ErrInvalidParamBgColor
+		return nil, nil, nil, ErrInvalidParamScheme
 	}
-	txtColor, err := getParamTextColor(ctx)
+	bgColor, err := getParamBgColor(ctx, preset.BackgroundColor, randomizer)
 	if err != nil {
-		return ErrInvalidParamTextColor
+		return nil, nil, nil, ErrInvalidParamBgColor
 	}
-	scale, err := getParamScale(ctx)
+	minContrast, err := getParamContrast(ctx)
 	if err != nil {
-		return ErrInvalidParamScale
+		return nil, nil, nil, ErrInvalidParamContrast
+	}
+	txtColorDefault := &preset.TextColor
+	if preset.AutoTextColor || randomizer.used {
+		// Preset text color may not be readable against random background
+		txtColorDefault = nil
+	}
+	txtColor, err := getParamTextColor(ctx, txtColorDefault, bgColor, randomizer, minContrast)
+	if err != nil {
+		return nil, nil, nil, textColorError(err)
+	}
+	scale, err := getParamScale(ctx, preset.Scale)
+	if err != nil {
+		return nil, nil, nil, ErrInvalidParamScale
+	}
+	font, err := getParamFont(ctx, preset.Font)
+	if err != nil {
+		return nil, nil, nil, ErrInvalidParamFont
+	}
+	defaultText := preset.Text
+	if defaultText == "" {
+		defaultText = defaultLabelFormat
+	}
+	variables := &textVariables{
+		width:  utils.ScaleDimension(size.Width, scale),
+		height: utils.ScaleDimension(size.Height, scale),
+		format: format,
+		scale:  scale,
+		seed:   ctx.Query(keySeed),
+		now:    time.Now(),
 	}
-	defaultText := fmt.Sprintf("%d %s %d", utils.ScaleDimension(size.Width, scale), dimensionDelimiter, utils.ScaleDimension(size.Height, scale))
 
-	text := getParamText(ctx, defaultText)
+	text, err := getParamGeneratedText(ctx, getParamText(ctx, defaultText), randomizer)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	text = variables.expand(text)
+	blocks, err := getParamTextBlocks(ctx, font, txtColor, randomizer, variables)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	overlay, err := getParamOverlay(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	frame, err := getParamFrame(ctx, txtColor)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if frame != nil {
+		if err := validateFrame(frame, size, scale); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	shape, matte, err := getParamShape(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	layout, err := getParamTextLayout(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	overflow, err := getParamTextOverflow(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	effects, err := getParamTextEffects(ctx, txtColor)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
-	params := &img.ImageParams{
+	return &img.ImageParams{
 		Format:          format,
 		Size:            size,
 		BackgroundColor: bgColor,
 		TextColor:       txtColor,
 		Scale:           scale,
 		Text:            text,
-	}
+		Blocks:          blocks,
+		Font:            font,
+		Overlay:         overlay,
+		Frame:           frame,
+		Shape:           shape,
+		Matte:           matte,
+		TextLayout:      layout,
+		Overflow:        overflow,
+		TextEffects:     effects,
+	}, randomizer, variables, nil
+}
 
-	result, err := img.Generate(params)
-	if err != nil {
-		return fiber.ErrInternalServerError
-	}
+// sendResult sends the generated image as response.
+func sendResult(ctx *fiber.Ctx, result *img.ImageResult) error {
 	ctx.Set("Content-Type", result.MimeType)
 	ctx.Set("Content-Length", string(result.Bytes))
 
 	return ctx.Send(result.Bytes)
 }
 
+// HandlerPathImage is a handler to serve image generation request for the path-style urls
+// used by other placeholder services e.g. /300, /300x200/png and /300x200/ff0000/ffffff.png.
+// The size in the path can also be an alias e.g. /leaderboard.png.
+//
+// The size, colors and format read from the path are given priority over the query parameters.
+// If no format is present in the path, [defaultFormat] is used.
+// If the size in the path is not valid, the request is passed on to the next matching route.
+func HandlerPathImage(ctx *fiber.Ctx) error {
+	sizeValue := ctx.Params(keySize)
+	if _, isAlias := lookupSizeAlias(sizeValue); !isAlias && !pathSizePattern.MatchString(sizeValue) {
+		return ctx.Next()
+	}
+	return HandlerImage(ctx)
+}
+
+// getParamValue returns the value of parameter with given key.
+//
+// The route parameter is given priority over the query parameter with the same key.
+func getParamValue(ctx *fiber.Ctx, key string) string {
+	return ctx.Params(key, ctx.Query(key))
+}
+
+// getParamPreset returns the preset read from route or query parameters.
+//
+// If no preset exists with the name, it returns nil, error.
+// If no preset is present in route or query parameters, it returns the default preset.
+func getParamPreset(ctx *fiber.Ctx) (*Preset, error) {
+	name := getParamValue(ctx, keyPreset)
+	if name == "" {
+		name = defaultPresetName
+	}
+	preset, exists := presets[name]
+	if !exists {
+		return nil, errors.New("preset not found with name " + name)
+	}
+	return preset, nil
+}
+
 // getParamSize returns the image size read from query parameters.
 //
+// If ratio is non-zero, the size may contain only one known dimension and the other dimension is computed
+// from the ratio, see [parseSizeWithRatio].
+//
 // If an error occurs, it returns nil, error.
-// If no size is present in query parameters, it returns defaultSize.
-func getParamSize(ctx *fiber.Ctx) (*img.Size, error) {
-	sizeParam := new(img.Size)
-	sizeParam.Height = defaultSize.Height
-	sizeParam.Width = defaultSize.Width
-
-	sizeValue := ctx.Query(keySize)
+// If no size is present in route or query parameters, it returns defaultValue.
+// But if ratio is non-zero, the height is computed from the width of defaultValue.
+func getParamSize(ctx *fiber.Ctx, defaultValue img.Size, ratio float64) (*img.Size, error) {
+	sizeValue := getParamValue(ctx, keySize)
+	if ratio != 0 {
+		if sizeValue == "" {
+			sizeValue = strconv.Itoa(defaultValue.Width)
+		}
+		return parseSizeWithRatio(sizeValue, ratio)
+	}
 	if sizeValue == "" {
-		return sizeParam, nil
+		return &img.Size{Width: defaultValue.Width, Height: defaultValue.Height}, nil
+	}
+	return parseSize(sizeValue)
+}
+
+// getParamRatio returns the aspect ratio (width / height) read from query parameters.
+//
+// If an error occurs, it returns 0.0, error.
+// If no aspect ratio is present in query parameters, it returns 0.0, nil.
+func getParamRatio(ctx *fiber.Ctx) (float64, error) {
+	ratioValue := ctx.Query(keyRatio)
+	if ratioValue == "" {
+		return 0.0, nil
+	}
+	return parseRatio(ratioValue)
+}
+
+// parseRatio returns the aspect ratio parsed from ratioValue in format <width>:<height>, <width>/<height> or <decimal>.
+// e.g. 16:9, 4/3 and 1.5 are valid aspect ratios.
+//
+// If ratioValue is not a valid positive ratio, it returns 0.0, error.
+func parseRatio(ratioValue string) (float64, error) {
+	var ratio float64
+	if index := strings.IndexAny(ratioValue, ratioDelimiters); index != -1 {
+		w, err := strconv.ParseFloat(ratioValue[:index], 64)
+		if err != nil {
+			return 0.0, err
+		}
+		h, err := strconv.ParseFloat(ratioValue[index+1:], 64)
+		if err != nil {
+			return 0.0, err
+		}
+		if h <= 0 {
+			return 0.0, errors.New("aspect ratio height must be positive")
+		}
+		ratio = w / h
+	} else {
+		r, err := strconv.ParseFloat(ratioValue, 64)
+		if err != nil {
+			return 0.0, err
+		}
+		ratio = r
+	}
+	if ratio <= 0 || math.IsInf(ratio, 0) || math.IsNaN(ratio) {
+		return 0.0, errors.New("aspect ratio must be positive")
+	}
+	return ratio, nil
+}
+
+// parseSizeWithRatio returns the image size parsed from sizeValue where an unknown dimension is computed from ratio.
+//
+// The sizeValue can be in one of the following formats -
+//
+// 1. 640 or 640xauto (Width 640, height computed from ratio)
+//
+// 2. autox360 (Height 360, width computed from ratio)
+//
+// 3. 640x360 or an alias e.g. leaderboard (Both dimensions known, ratio is ignored)
+//
+// If an error occurs while parsing, it returns nil, error.
+func parseSizeWithRatio(sizeValue string, ratio float64) (*img.Size, error) {
+	if size, exists := lookupSizeAlias(sizeValue); exists {
+		return size, nil
+	}
+	sizeValue = strings.ToLower(sizeValue)
+
+	w, h := sizeValue, autoDimension
+	if strings.Contains(sizeValue, dimensionDelimiter) {
+		dimensions := strings.Split(sizeValue, dimensionDelimiter)
+		w, h = dimensions[widthIndex], dimensions[heightIndex]
+	}
+
+	switch {
+	case w == autoDimension && h == autoDimension:
+		return nil, errors.New("at least one dimension must be known")
+	case w == autoDimension:
+		height, err := strconv.Atoi(h)
+		if err != nil {
+			return nil, err
+		}
+		return &img.Size{Width: ratioDimension(float64(height) * ratio), Height: height}, nil
+	case h == autoDimension:
+		width, err := strconv.Atoi(w)
+		if err != nil {
+			return nil, err
+		}
+		return &img.Size{Width: width, Height: ratioDimension(float64(width) / ratio)}, nil
+	default:
+		return parseSize(sizeValue)
+	}
+}
+
+// ratioDimension returns the dimension computed from aspect ratio rounded to the nearest integer.
+// It returns at least 1 so that the image is never empty.
+func ratioDimension(dimension float64) int {
+	return int(math.Max(1, math.Round(dimension)))
+}
+
+// parseSize returns the image size parsed from sizeValue in format <width>x<height> or <side>,
+// or the size of a well-known alias e.g. leaderboard, see [SizeAlias].
+//
+// If an error occurs while parsing, it returns nil, error.
+func parseSize(sizeValue string) (*img.Size, error) {
+	if size, exists := lookupSizeAlias(sizeValue); exists {
+		return size, nil
 	}
 	sizeValue = strings.ToLower(sizeValue)
 
@@ -146,84 +407,80 @@ func getParamSize(ctx *fiber.Ctx) (*img.Size, error) {
 	if err != nil {
 		return nil, err
 	}
-	sizeParam.Width = width
-	sizeParam.Height = height
-	return sizeParam, nil
+	return &img.Size{Width: width, Height: height}, nil
 }
 
 // getParamBgColor returns the image background color read from query parameters.
 //
 // If an error occurs, it return nil, error.
-// If no background color is present in query parameters, it returns defaultBgColor.
-func getParamBgColor(ctx *fiber.Ctx) (*img.Color, error) {
-	bgColorParam := new(img.Color)
-	bgColorParam.R = defaultBgColor.R
-	bgColorParam.G = defaultBgColor.G
-	bgColorParam.B = defaultBgColor.B
-
-	bgColorValue := ctx.Query(keyBgColor)
+// If no background color is present in query parameters, it returns defaultValue.
+// If background color is random, it returns a color picked by randomizer.
+func getParamBgColor(ctx *fiber.Ctx, defaultValue img.Color, randomizer *colorRandomizer) (*img.Color, error) {
+	bgColorValue := getParamValue(ctx, keyBgColor)
 
 	if bgColorValue == "" {
-		return bgColorParam, nil
+		return &img.Color{R: defaultValue.R, G: defaultValue.G, B: defaultValue.B}, nil
 	}
-
-	color, err := stringutils.ParseColorHex(bgColorValue)
-	if err != nil {
-		return nil, err
+	if bgColorValue == colorRandom {
+		return randomizer.background(), nil
 	}
-	red, green, blue := utils.GetRGBComponents(color)
-
-	bgColorParam.R = red
-	bgColorParam.G = green
-	bgColorParam.B = blue
-
-	return bgColorParam, nil
+	return parseColor(bgColorValue)
 }
 
 // getParamTextColor returns the image text color read from query parameters.
 //
 // If an error occurs, it return nil, error.
-// If no text color is present in query parameters, it returns defaultTextColor.
-func getParamTextColor(ctx *fiber.Ctx) (*img.Color, error) {
-	txtColorParam := new(img.Color)
-	txtColorParam.R = defaultTextColor.R
-	txtColorParam.G = defaultTextColor.G
-	txtColorParam.B = defaultTextColor.B
+// If no text color is present in query parameters, it returns defaultValue, or the auto color if defaultValue is nil.
+// If text color is random, it returns a color picked by randomizer which has at least minContrast ratio against bgColor.
+// If text color is auto, it returns a color which has at least minContrast ratio against bgColor.
+// If no color has minContrast ratio against bgColor, it returns nil, [img.ErrContrastRatio].
+func getParamTextColor(ctx *fiber.Ctx, defaultValue *img.Color, bgColor *img.Color, randomizer *colorRandomizer, minContrast float64) (*img.Color, error) {
+	txtColorValue := getParamValue(ctx, keyTextColor)
 
-	txtColorValue := ctx.Query(keyTextColor)
-
-	if txtColorValue == "" {
-		return txtColorParam, nil
+	if txtColorValue == "" && defaultValue != nil {
+		return &img.Color{R: defaultValue.R, G: defaultValue.G, B: defaultValue.B}, nil
+	}
+	if txtColorValue == colorRandom {
+		return randomizer.text(bgColor, minContrast)
+	}
+	if txtColorValue == "" || txtColorValue == colorAuto {
+		color, err := img.AccessibleTextColor(*bgColor, minContrast)
+		if err != nil {
+			return nil, err
+		}
+		return &color, nil
 	}
+	return parseColor(txtColorValue)
+}
 
-	color, err := stringutils.ParseColorHex(txtColorValue)
+// parseColor returns the color parsed from hexadecimal colorValue e.g. FA3 or FFAA33.
+//
+// If an error occurs while parsing, it returns nil, error.
+func parseColor(colorValue string) (*img.Color, error) {
+	color, err := stringutils.ParseColorHex(colorValue)
 	if err != nil {
 		return nil, err
 	}
 	red, green, blue := utils.GetRGBComponents(color)
 
-	txtColorParam.R = red
-	txtColorParam.G = green
-	txtColorParam.B = blue
-
-	return txtColorParam, nil
+	return &img.Color{R: red, G: green, B: blue}, nil
 }
 
 // getParamText returns the image text read from query parameters.
 //
-// If no text is present in query parameters, it returns defaultValue.
+// If no text is present in query parameters, it looks for the text under [keyTextAlias] and then returns defaultValue.
 func getParamText(ctx *fiber.Ctx, defaultValue string) string {
-	return ctx.Query(keyText, defaultValue)
+	return ctx.Query(keyText, ctx.Query(keyTextAlias, defaultValue))
 }
 
 // getParamScale returns the image scale read from query parameters.
 //
 // If an error occurs, it returns 0.0, error.
-// If no scale is present in query parameters, it returns defaultScale.
-func getParamScale(ctx *fiber.Ctx) (float64, error) {
+// If no scale is present in query parameters, it returns defaultValue.
+func getParamScale(ctx *fiber.Ctx, defaultValue float64) (float64, error) {
 	scaleValue := ctx.Query(keyScale)
 	if scaleValue == "" {
-		return defaultScale, nil
+		return defaultValue, nil
 	}
 	scaleParam, err := strconv.ParseFloat(scaleValue, 32)
 	if err != nil {
@@ -231,3 +488,15 @@ func getParamScale(ctx *fiber.Ctx) (float64, error) {
 	}
 	return float64(scaleParam), nil
 }
+
+// getParamFont returns the name of the text font read from query parameters.
+//
+// If no font is registered with the name, it returns "", error.
+// If no font is present in query parameters, it returns defaultValue.
+func getParamFont(ctx *fiber.Ctx, defaultValue string) (string, error) {
+	fontValue := ctx.Query(keyFont, defaultValue)
+	if !img.HasFont(fontValue) {
+		return "", errors.New("font not found with name " + fontValue)
+	}
+	return fontValue, nil
+}
@@ -2,6 +2,10 @@ package server
 
 import (
 	"bytes"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	"image/png"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -224,3 +228,145 @@ func TestHandlerImage(t *testing.T) {
 		}
 	}
 }
+
+type TestDataPath struct {
+	Route              string
+	ExpectedStatusCode int
+	ExpectedType       string
+}
+
+var testDataPath = []TestDataPath{
+	{Route: "/300", ExpectedStatusCode: 200, ExpectedType: "image/png"},
+	{Route: "/300x200", ExpectedStatusCode: 200, ExpectedType: "image/png"},
+	{Route: "/300x200/jpg", ExpectedStatusCode: 200, ExpectedType: "image/jpg"},
+	{Route: "/300x200/ff0000/ffffff", ExpectedStatusCode: 200, ExpectedType: "image/png"},
+	{Route: "/300x200/ff0000/ffffff.jpeg", ExpectedStatusCode: 200, ExpectedType: "image/jpeg"},
+	{Route: "/300x200.tiff?text=Hello", ExpectedStatusCode: 200, ExpectedType: "image/tiff"},
+	{Route: "/300x200/gif", ExpectedStatusCode: 400, ExpectedType: ""},
+	{Route: "/300x200/ff0000/fffz", ExpectedStatusCode: 400, ExpectedType: ""},
+	{Route: "/300y200", ExpectedStatusCode: 404, ExpectedType: ""},
+	{Route: "/abcd/png", ExpectedStatusCode: 404, ExpectedType: ""},
+	{Route: "/abcd.png", ExpectedStatusCode: 404, ExpectedType: ""},
+}
+
+func TestHandlerPathImage(t *testing.T) {
+	router := fiber.New()
+	registerRoutes(router)
+
+	for _, data := range testDataPath {
+		req := httptest.NewRequest(http.MethodGet, data.Route, nil)
+		res, err := router.Test(req, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data.ExpectedStatusCode != res.StatusCode {
+			t.Fatalf("\nroute = %s\nexpected status code = %d\nactual status code = %d\n", data.Route, data.ExpectedStatusCode, res.StatusCode)
+		}
+		if expectedType, actualType := data.ExpectedType, res.Header.Get("content-type"); expectedType != "" && expectedType != actualType {
+			t.Fatalf("\nroute = %s\nexpected content type = %s\nactual content type = %s\n", data.Route, expectedType, actualType)
+		}
+	}
+}
+
+func TestParseRatio(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{value: "16:9", want: 16.0 / 9.0},
+		{value: "4/3", want: 4.0 / 3.0},
+		{value: "1.5", want: 1.5},
+		{value: "1:0", wantErr: true},
+		{value: "0:1", wantErr: true},
+		{value: "-2", wantErr: true},
+		{value: "a:b", wantErr: true},
+		{value: "wide", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseRatio(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRatio() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseRatio() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlerImageRatio(t *testing.T) {
+	router := fiber.New()
+	registerRoutes(router)
+
+	tests := []struct {
+		query              string
+		expectedStatusCode int
+		expectedWidth      int
+		expectedHeight     int
+	}{
+		{query: "s=640&ar=16:9", expectedStatusCode: 200, expectedWidth: 640, expectedHeight: 360},
+		{query: "s=640xauto&ar=4:3", expectedStatusCode: 200, expectedWidth: 640, expectedHeight: 480},
+		{query: "s=autox300&ar=2", expectedStatusCode: 200, expectedWidth: 600, expectedHeight: 300},
+		{query: "s=200x50&ar=16:9", expectedStatusCode: 200, expectedWidth: 200, expectedHeight: 50},
+		{query: "ar=1:2", expectedStatusCode: 200, expectedWidth: 100, expectedHeight: 200},
+		{query: "s=100&ar=16:9&x=2", expectedStatusCode: 200, expectedWidth: 200, expectedHeight: 112},
+		{query: "s=autoxauto&ar=16:9", expectedStatusCode: 400},
+		{query: "s=640xauto", expectedStatusCode: 400},
+		{query: "s=640&ar=16:0", expectedStatusCode: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			res, err := router.Test(httptest.NewRequest(http.MethodGet, "/png?"+tt.query, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.expectedStatusCode {
+				t.Fatalf("expected status code = %d, actual status code = %d", tt.expectedStatusCode, res.StatusCode)
+			}
+			if tt.expectedStatusCode != 200 {
+				return
+			}
+			config, err := png.DecodeConfig(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.Width != tt.expectedWidth || config.Height != tt.expectedHeight {
+				t.Errorf("expected size = %dx%d, actual size = %dx%d", tt.expectedWidth, tt.expectedHeight, config.Width, config.Height)
+			}
+		})
+	}
+}
+
+// getImage requests the route from router and returns the decoded image, failing the test unless it succeeds.
+func getImage(t *testing.T, router *fiber.App, route string) image.Image {
+	t.Helper()
+	res, err := router.Test(httptest.NewRequest(http.MethodGet, route, nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != 200 {
+		t.Fatalf("route = %s, expected status code = 200, actual status code = %d", route, res.StatusCode)
+	}
+	decoded, _, err := image.Decode(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+// colorBounds returns the smallest rectangle containing the pixels of the image which are exactly of color c.
+// If no pixel is of color c, it returns an empty rectangle.
+func colorBounds(decoded image.Image, c color.Color) image.Rectangle {
+	bounds := image.Rectangle{}
+	r, g, b, a := c.RGBA()
+	for y := decoded.Bounds().Min.Y; y < decoded.Bounds().Max.Y; y++ {
+		for x := decoded.Bounds().Min.X; x < decoded.Bounds().Max.X; x++ {
+			if pr, pg, pb, pa := decoded.At(x, y).RGBA(); pr == r && pg == g && pb == b && pa == a {
+				bounds = bounds.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return bounds
+}
@@ -0,0 +1,227 @@
+package server
+
+import (
+	"encoding/base64"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/cod3rboy/yaps/utils"
+	"github.com/cod3rboy/yaps/utils/sliceutils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Constants for hash query parameter keys
+const (
+	keyHash       = "hash"
+	keyPunch      = "punch"
+	keyComponents = "components"
+)
+
+// Client Errors for hashes
+var (
+	ErrInvalidParamHash       = fiber.NewError(fiber.ErrBadRequest.Code, "invalid hash ("+keyHash+") value")
+	ErrInvalidParamPunch      = fiber.NewError(fiber.ErrBadRequest.Code, "invalid punch ("+keyPunch+") value")
+	ErrInvalidParamComponents = fiber.NewError(fiber.ErrBadRequest.Code, "invalid components ("+keyComponents+") value")
+)
+
+// Default values for hash parameters
+var (
+	defaultBlurHashSize       = img.Size{Width: 32, Height: 32}
+	defaultBlurHashPunch      = 1.0
+	defaultBlurHashComponents = img.Size{Width: 4, Height: 3}
+)
+
+// A HashResult stores the hashes computed for a generated image.
+type HashResult struct {
+	Width     int    `json:"width"`     // Width of the image
+	Height    int    `json:"height"`    // Height of the image
+	BlurHash  string `json:"blurHash"`  // BlurHash of the image
+	ThumbHash string `json:"thumbHash"` // ThumbHash of the image in base64 encoding
+}
+
+// HandlerBlurHash is a handler to serve image generation request for a BlurHash.
+//
+// The hash is decoded directly at the requested size, so the preview stays smooth at any size.
+func HandlerBlurHash(ctx *fiber.Ctx) error {
+	format := ctx.Params(keyFormat)
+	if !sliceutils.ContainsString(SupportedFormats, format) {
+		return ErrUnsupportedFormat
+	}
+	hash := ctx.Query(keyHash)
+	if hash == "" {
+		return ErrInvalidParamHash
+	}
+	ratio, err := getParamRatio(ctx)
+	if err != nil {
+		return ErrInvalidParamRatio
+	}
+	size, err := getParamSize(ctx, defaultBlurHashSize, ratio)
+	if err != nil {
+		return ErrInvalidParamSize
+	}
+	scale, err := getParamScale(ctx, presets[defaultPresetName].Scale)
+	if err != nil {
+		return ErrInvalidParamScale
+	}
+	punch, err := getParamPunch(ctx)
+	if err != nil {
+		return ErrInvalidParamPunch
+	}
+	width, height := utils.ScaleDimension(size.Width, scale), utils.ScaleDimension(size.Height, scale)
+	if err := authorizeRender(ctx, format, width, height); err != nil {
+		return err
+	}
+
+	preview, err := img.DecodeBlurHash(hash, width, height, punch)
+	if err != nil {
+		return ErrInvalidParamHash
+	}
+	result, err := img.GeneratePreview(&img.PreviewParams{
+		Format:  format,
+		Size:    &img.Size{Width: width, Height: height},
+		Preview: preview,
+		Scale:   1,
+	})
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	return sendResult(ctx, result)
+}
+
+// HandlerThumbHash is a handler to serve image generation request for a base64 encoded ThumbHash.
+//
+// Without an aspect ratio query parameter, the aspect ratio stored in the hash is used to compute the size.
+func HandlerThumbHash(ctx *fiber.Ctx) error {
+	format := ctx.Params(keyFormat)
+	if !sliceutils.ContainsString(SupportedFormats, format) {
+		return ErrUnsupportedFormat
+	}
+	hash, err := parseThumbHash(ctx.Query(keyHash))
+	if err != nil {
+		return ErrInvalidParamHash
+	}
+	preview, err := img.DecodeThumbHash(hash)
+	if err != nil {
+		return ErrInvalidParamHash
+	}
+	ratio, err := getParamRatio(ctx)
+	if err != nil {
+		return ErrInvalidParamRatio
+	}
+	if ratio == 0 {
+		ratio, _ = img.ThumbHashAspectRatio(hash)
+	}
+	bounds := preview.Bounds()
+	size, err := getParamSize(ctx, img.Size{Width: bounds.Dx(), Height: bounds.Dy()}, ratio)
+	if err != nil {
+		return ErrInvalidParamSize
+	}
+	scale, err := getParamScale(ctx, presets[defaultPresetName].Scale)
+	if err != nil {
+		return ErrInvalidParamScale
+	}
+	if err := authorizeRender(ctx, format, utils.ScaleDimension(size.Width, scale), utils.ScaleDimension(size.Height, scale)); err != nil {
+		return err
+	}
+
+	result, err := img.GeneratePreview(&img.PreviewParams{
+		Format:  format,
+		Size:    size,
+		Preview: preview,
+		Scale:   scale,
+	})
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	return sendResult(ctx, result)
+}
+
+// HandlerHash is a handler which responds with the BlurHash and ThumbHash of the image
+// generated for the same query parameters as [HandlerImage].
+func HandlerHash(ctx *fiber.Ctx) error {
+	params, _, _, err := getParamImage(ctx, defaultFormat)
+	if err != nil {
+		return err
+	}
+	components, err := getParamComponents(ctx)
+	if err != nil {
+		return ErrInvalidParamComponents
+	}
+	width, height := utils.ScaleDimension(params.Width, params.Scale), utils.ScaleDimension(params.Height, params.Scale)
+	if err := authorizeRender(ctx, params.Format, width, height); err != nil {
+		return err
+	}
+
+	rendered, err := img.Render(params)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	blurHash, err := img.EncodeBlurHash(rendered, components.Width, components.Height)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	thumbHash, err := img.EncodeThumbHash(rendered)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	return ctx.JSON(&HashResult{
+		Width:     width,
+		Height:    height,
+		BlurHash:  blurHash,
+		ThumbHash: base64.StdEncoding.EncodeToString(thumbHash),
+	})
+}
+
+// getParamPunch returns the BlurHash punch read from query parameters.
+//
+// If the punch is not a positive finite number, it returns 0.0, error.
+// If no punch is present in query parameters, it returns defaultBlurHashPunch.
+func getParamPunch(ctx *fiber.Ctx) (float64, error) {
+	punchValue := ctx.Query(keyPunch)
+	if punchValue == "" {
+		return defaultBlurHashPunch, nil
+	}
+	punch, err := strconv.ParseFloat(punchValue, 64)
+	if err != nil {
+		return 0.0, err
+	}
+	if math.IsNaN(punch) || math.IsInf(punch, 0) || punch <= 0 {
+		return 0.0, errors.New("punch must be a positive finite number")
+	}
+	return punch, nil
+}
+
+// getParamComponents returns the number of BlurHash components in each direction read from query parameters
+// in format XxY e.g. 4x3.
+//
+// If the components are not in range [img.MinBlurHashComponents, img.MaxBlurHashComponents], it returns nil, error.
+// If no components are present in query parameters, it returns defaultBlurHashComponents.
+func getParamComponents(ctx *fiber.Ctx) (*img.Size, error) {
+	componentsValue := ctx.Query(keyComponents)
+	if componentsValue == "" {
+		return &img.Size{Width: defaultBlurHashComponents.Width, Height: defaultBlurHashComponents.Height}, nil
+	}
+	components, err := parseSize(componentsValue)
+	if err != nil {
+		return nil, err
+	}
+	if components.Width < img.MinBlurHashComponents || components.Width > img.MaxBlurHashComponents ||
+		components.Height < img.MinBlurHashComponents || components.Height > img.MaxBlurHashComponents {
+		return nil, errors.New("components out of range")
+	}
+	return components, nil
+}
+
+// parseThumbHash decodes the ThumbHash from standard or url-safe base64 encoding, with or without padding.
+//
+// A + sign decoded as a space from the query string is accepted as well.
+func parseThumbHash(hashValue string) ([]byte, error) {
+	if hashValue == "" {
+		return nil, errors.New("empty thumbhash")
+	}
+	hashValue = strings.NewReplacer(" ", "+", "-", "+", "_", "/").Replace(strings.TrimRight(hashValue, "="))
+	return base64.RawStdEncoding.DecodeString(hashValue)
+}
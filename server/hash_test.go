@@ -0,0 +1,90 @@
+package server
+
+import (
+	"encoding/json"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestHandlerHash(t *testing.T) {
+	router := fiber.New()
+	registerRoutes(router)
+
+	res, err := router.Test(httptest.NewRequest(http.MethodGet, "/hash?s=200x100&b=334155&components=5x4", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != 200 {
+		t.Fatalf("expected status code = 200, actual status code = %d", res.StatusCode)
+	}
+	result := &HashResult{}
+	if err := json.NewDecoder(res.Body).Decode(result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Width != 200 || result.Height != 100 {
+		t.Errorf("expected size = 200x100, actual size = %dx%d", result.Width, result.Height)
+	}
+	// 5x4 components take 4 + 2 * 20 characters
+	if len(result.BlurHash) != 44 {
+		t.Errorf("expected blurhash length = 44, actual blurhash = %s", result.BlurHash)
+	}
+
+	// Computed hashes are decoded back into images by the hash routes
+	tests := []struct {
+		route         string
+		width, height int
+	}{
+		{route: "/blurhash/png?s=64x48&hash=" + url.QueryEscape(result.BlurHash), width: 64, height: 48},
+		{route: "/blurhash/png?s=64&ar=2&x=2&hash=" + url.QueryEscape(result.BlurHash), width: 128, height: 64},
+		{route: "/thumbhash/png?s=300x100&hash=" + url.QueryEscape(result.ThumbHash), width: 300, height: 100},
+		// ThumbHash stores an approximate aspect ratio of 7:4 for 2:1 images
+		{route: "/thumbhash/png?s=300&hash=" + url.QueryEscape(result.ThumbHash), width: 300, height: 171},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			res, err := router.Test(httptest.NewRequest(http.MethodGet, tt.route, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != 200 {
+				t.Fatalf("expected status code = 200, actual status code = %d", res.StatusCode)
+			}
+			decoded, err := png.Decode(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if size := decoded.Bounds().Size(); size.X != tt.width || size.Y != tt.height {
+				t.Errorf("expected size = %dx%d, actual size = %dx%d", tt.width, tt.height, size.X, size.Y)
+			}
+		})
+	}
+}
+
+func TestHandlerHashInvalid(t *testing.T) {
+	router := fiber.New()
+	registerRoutes(router)
+
+	for _, route := range []string{
+		"/blurhash/png",
+		"/blurhash/png?hash=L0",
+		"/blurhash/png?hash=LEHV6nWB2yk8pyo0adR*.7kCMdnj&punch=0",
+		"/blurhash/png?hash=LEHV6nWB2yk8pyo0adR*.7kCMdnj&punch=NaN",
+		"/blurhash/png?hash=LEHV6nWB2yk8pyo0adR*.7kCMdnj&punch=Inf",
+		"/thumbhash/png?hash=!!",
+		"/thumbhash/png?hash=AQID",
+		"/hash?components=10x3",
+	} {
+		res, err := router.Test(httptest.NewRequest(http.MethodGet, route, nil), -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != 400 {
+			t.Errorf("route = %s, expected status code = 400, actual status code = %d", route, res.StatusCode)
+		}
+	}
+}
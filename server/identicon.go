@@ -0,0 +1,150 @@
+package server
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/cod3rboy/yaps/utils"
+	"github.com/cod3rboy/yaps/utils/sliceutils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Constants for identicon query parameter keys
+const (
+	keySeed    = "seed"
+	keyGrid    = "grid"
+	keyPadding = "p"
+)
+
+// Client Errors for identicons
+var (
+	ErrInvalidParamSeed    = fiber.NewError(fiber.ErrBadRequest.Code, "invalid seed ("+keySeed+") value")
+	ErrInvalidParamGrid    = fiber.NewError(fiber.ErrBadRequest.Code, "invalid grid ("+keyGrid+") value")
+	ErrInvalidParamPadding = fiber.NewError(fiber.ErrBadRequest.Code, "invalid padding ("+keyPadding+") value")
+)
+
+// Default values for identicon parameters
+var (
+	defaultIdenticonSize    = img.Size{Width: 128, Height: 128}
+	defaultIdenticonGrid    = 5
+	defaultIdenticonPadding = 0.08
+	defaultIdenticonBgColor = img.Color{
+		// Off White (#f0f0f0)
+		R: 0xF0,
+		G: 0xF0,
+		B: 0xF0,
+	}
+)
+
+// HandlerIdenticon is a handler to serve identicon generation request for a seed string.
+//
+// The block pattern and its color are derived from the seed, so the same seed always gets the same identicon.
+// The block color can be overridden with the text color query parameter.
+func HandlerIdenticon(ctx *fiber.Ctx) error {
+	format := ctx.Params(keyFormat)
+	if !sliceutils.ContainsString(SupportedFormats, format) {
+		return ErrUnsupportedFormat
+	}
+	seed := ctx.Query(keySeed)
+	if seed == "" {
+		return ErrInvalidParamSeed
+	}
+
+	size, err := getParamSize(ctx, defaultIdenticonSize, 0)
+	if err != nil {
+		return ErrInvalidParamSize
+	}
+	randomizer, err := newColorRandomizer(ctx)
+	if err != nil {
+		return ErrInvalidParamScheme
+	}
+	bgColor, err := getParamBgColor(ctx, defaultIdenticonBgColor, randomizer)
+	if err != nil {
+		return ErrInvalidParamBgColor
+	}
+	minContrast, err := getParamContrast(ctx)
+	if err != nil {
+		return ErrInvalidParamContrast
+	}
+	identiconColor := img.IdenticonColor(seed)
+	fgColor, err := getParamTextColor(ctx, &identiconColor, bgColor, randomizer, minContrast)
+	if err != nil {
+		return textColorError(err)
+	}
+	scale, err := getParamScale(ctx, presets[defaultPresetName].Scale)
+	if err != nil {
+		return ErrInvalidParamScale
+	}
+	grid, err := getParamGrid(ctx)
+	if err != nil {
+		return ErrInvalidParamGrid
+	}
+	padding, err := getParamPadding(ctx)
+	if err != nil {
+		return ErrInvalidParamPadding
+	}
+	if err := authorizeRender(ctx, format, utils.ScaleDimension(size.Width, scale), utils.ScaleDimension(size.Height, scale)); err != nil {
+		return err
+	}
+
+	params := &img.IdenticonParams{
+		Format:          format,
+		Size:            size,
+		Seed:            seed,
+		Grid:            grid,
+		Padding:         padding,
+		BackgroundColor: bgColor,
+		ForegroundColor: fgColor,
+		Scale:           scale,
+	}
+
+	result, err := img.GenerateIdenticon(params)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	if !randomizer.cacheable() {
+		ctx.Set(fiber.HeaderCacheControl, "no-store")
+	}
+	ctx.Set(headerContrastRatio, formatContrastRatio(img.ContrastRatio(*bgColor, *fgColor)))
+	return sendResult(ctx, result)
+}
+
+// getParamGrid returns the identicon grid size read from query parameters.
+//
+// If the grid is not a number in range [img.MinIdenticonGrid, img.MaxIdenticonGrid], it returns 0, error.
+// If no grid is present in query parameters, it returns defaultIdenticonGrid.
+func getParamGrid(ctx *fiber.Ctx) (int, error) {
+	gridValue := ctx.Query(keyGrid)
+	if gridValue == "" {
+		return defaultIdenticonGrid, nil
+	}
+	grid, err := strconv.Atoi(gridValue)
+	if err != nil {
+		return 0, err
+	}
+	if grid < img.MinIdenticonGrid || grid > img.MaxIdenticonGrid {
+		return 0, errors.New("grid out of range")
+	}
+	return grid, nil
+}
+
+// getParamPadding returns the identicon padding read from query parameters.
+//
+// If the padding is not a number in range [0, 0.5), it returns 0.0, error.
+// If no padding is present in query parameters, it returns defaultIdenticonPadding.
+func getParamPadding(ctx *fiber.Ctx) (float64, error) {
+	paddingValue := ctx.Query(keyPadding)
+	if paddingValue == "" {
+		return defaultIdenticonPadding, nil
+	}
+	padding, err := strconv.ParseFloat(paddingValue, 64)
+	if err != nil {
+		return 0.0, err
+	}
+	if math.IsNaN(padding) || math.IsInf(padding, 0) || padding < 0 || padding >= 0.5 {
+		return 0.0, errors.New("padding out of range")
+	}
+	return padding, nil
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestHandlerIdenticon(t *testing.T) {
+	router := fiber.New()
+	registerRoutes(router)
+
+	tests := []struct {
+		route              string
+		expectedStatusCode int
+		expectedSize       int
+	}{
+		{route: "/identicon/png?seed=jane", expectedStatusCode: 200, expectedSize: 128},
+		{route: "/identicon/png?seed=jane&grid=8&p=0.2&s=64&x=2&b=fff", expectedStatusCode: 200, expectedSize: 128},
+		{route: "/identicon/png", expectedStatusCode: 400},
+		{route: "/identicon/gif?seed=jane", expectedStatusCode: 400},
+		{route: "/identicon/png?seed=jane&grid=2", expectedStatusCode: 400},
+		{route: "/identicon/png?seed=jane&grid=five", expectedStatusCode: 400},
+		{route: "/identicon/png?seed=jane&p=0.5", expectedStatusCode: 400},
+		{route: "/identicon/png?seed=jane&p=NaN", expectedStatusCode: 400},
+		{route: "/identicon/png?seed=jane&p=-Inf", expectedStatusCode: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			res, err := router.Test(httptest.NewRequest(http.MethodGet, tt.route, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.expectedStatusCode {
+				t.Fatalf("expected status code = %d, actual status code = %d", tt.expectedStatusCode, res.StatusCode)
+			}
+			if tt.expectedSize == 0 {
+				return
+			}
+			config, err := png.DecodeConfig(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.Width != tt.expectedSize || config.Height != tt.expectedSize {
+				t.Errorf("expected size = %dx%d, actual size = %dx%d", tt.expectedSize, tt.expectedSize, config.Width, config.Height)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Pattern of a text variable e.g. {w} or {date:2006-01-02}, with the name and the optional argument as submatches
+var textVariablePattern = regexp.MustCompile(`\{([a-z]+)(?::([^{}]*))?\}`)
+
+// Default layout of the date variable without argument
+const defaultDateLayout = "2006-01-02"
+
+// Default template of the image text, can be overridden with [LoadLabelFormat]
+var defaultLabelFormat = "{w} x {h}"
+
+// Values of the variables which can be used in the image text.
+type textVariables struct {
+	width, height int       // Final dimensions of the image after scaling
+	format        string    // Image format e.g. png
+	scale         float64   // Scaling factor of the image
+	seed          string    // Seed of the request, empty without seed
+	now           time.Time // Time of the request
+	dated         bool      // Whether the date variable was expanded
+}
+
+// LoadLabelFormat sets the default template of the image text to format e.g. {w}x{h} @{scale}x.
+//
+// If format uses an unknown variable, the default is not changed and it returns an error.
+func LoadLabelFormat(format string) error {
+	for _, match := range textVariablePattern.FindAllStringSubmatch(format, -1) {
+		if _, known := (&textVariables{}).value(match[1], match[2]); !known {
+			return errors.New("unknown text variable " + match[0])
+		}
+	}
+	defaultLabelFormat = format
+	return nil
+}
+
+// expand returns the text with each known variable replaced by its value.
+//
+// Unknown variables and other text in braces are kept as they are.
+func (v *textVariables) expand(text string) string {
+	if !strings.Contains(text, "{") {
+		return text
+	}
+	return textVariablePattern.ReplaceAllStringFunc(text, func(variable string) string {
+		match := textVariablePattern.FindStringSubmatch(variable)
+		if value, known := v.value(match[1], match[2]); known {
+			return value
+		}
+		return variable
+	})
+}
+
+// value returns the value of the variable with given name and argument, and whether the variable is known.
+//
+// The variables are:
+//   - w and h, the width and height in pixels.
+//   - ratio, the aspect ratio in lowest terms e.g. 16:9.
+//   - format, the image format.
+//   - scale, the scaling factor e.g. 2 or 1.5.
+//   - bytes, or its alias rawbytes, the size of the uncompressed RGBA image in bytes, not the size of the encoded image.
+//   - seed, the seed query parameter.
+//   - date, the current UTC date formatted with the Go time layout in the argument, by default 2006-01-02.
+//
+// The date variable changes with time, so the text is marked as dated, see [textVariables.cacheable].
+func (v *textVariables) value(name, argument string) (string, bool) {
+	switch name {
+	case "w":
+		return strconv.Itoa(v.width), true
+	case "h":
+		return strconv.Itoa(v.height), true
+	case "ratio":
+		divisor := gcd(v.width, v.height)
+		if divisor == 0 {
+			return "0:0", true
+		}
+		return strconv.Itoa(v.width/divisor) + ":" + strconv.Itoa(v.height/divisor), true
+	case "format":
+		return v.format, true
+	case "scale":
+		return strconv.FormatFloat(v.scale, 'f', -1, 64), true
+	case "bytes", "rawbytes":
+		return strconv.Itoa(v.width * v.height * 4), true
+	case "seed":
+		return v.seed, true
+	case "date":
+		if argument == "" {
+			argument = defaultDateLayout
+		}
+		v.dated = true
+		return v.now.UTC().Format(argument), true
+	}
+	return "", false
+}
+
+// cacheable returns false if an expanded text contains the date variable otherwise it returns true.
+func (v *textVariables) cacheable() bool {
+	return !v.dated
+}
+
+// gcd returns the greatest common divisor of a and b.
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTextVariablesExpand(t *testing.T) {
+	variables := textVariables{
+		width:  1440,
+		height: 600,
+		format: "webp",
+		scale:  2,
+		seed:   "product-42",
+		now:    time.Date(2026, time.March, 14, 9, 30, 0, 0, time.UTC),
+	}
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "{w} x {h}", want: "1440 x 600"},
+		{text: "hero {w}x{h} @{scale}x", want: "hero 1440x600 @2x"},
+		{text: "{ratio} {format}", want: "12:5 webp"},
+		{text: "{bytes} bytes", want: "3456000 bytes"},
+		{text: "{rawbytes}", want: "3456000"},
+		{text: "seed {seed}", want: "seed product-42"},
+		{text: "{date}", want: "2026-03-14"},
+		{text: "{date:02 Jan 15:04}", want: "14 Mar 09:30"},
+		{text: "{unknown} {W} {}", want: "{unknown} {W} {}"},
+		{text: "Hello World", want: "Hello World"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := variables.expand(tt.text); got != tt.want {
+				t.Errorf("expand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if got := (&textVariables{scale: 1.5}).expand("{scale} {ratio}"); got != "1.5 0:0" {
+		t.Errorf("expand() = %q, want %q", got, "1.5 0:0")
+	}
+}
+
+func TestLoadLabelFormat(t *testing.T) {
+	defer func() { defaultLabelFormat = "{w} x {h}" }()
+
+	if err := LoadLabelFormat("{w}x{h} @{scale}x"); err != nil {
+		t.Fatal(err)
+	}
+	if defaultLabelFormat != "{w}x{h} @{scale}x" {
+		t.Errorf("expected default label format = {w}x{h} @{scale}x, actual = %s", defaultLabelFormat)
+	}
+	if err := LoadLabelFormat("{width} x {height}"); err == nil {
+		t.Errorf("expected error for unknown variable")
+	}
+	if defaultLabelFormat != "{w}x{h} @{scale}x" {
+		t.Errorf("expected default label format to be unchanged on error")
+	}
+}
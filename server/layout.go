@@ -0,0 +1,56 @@
+package server
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Constants for text layout query parameter keys
+const (
+	keyRotate  = "rotate"
+	keyWriting = "writing"
+)
+
+// Values of text layout parameters
+const (
+	rotateDiagonal    = "diagonal"
+	writingHorizontal = "horizontal"
+	writingVertical   = "vertical"
+)
+
+// Client Errors for text layout
+var (
+	ErrInvalidParamRotate  = fiber.NewError(fiber.ErrBadRequest.Code, "invalid rotate ("+keyRotate+") value")
+	ErrInvalidParamWriting = fiber.NewError(fiber.ErrBadRequest.Code, "invalid writing ("+keyWriting+") value")
+)
+
+// getParamTextLayout returns the text layout read from query parameters.
+//
+// The rotation is either an angle in degrees, clockwise, or diagonal. The writing mode is horizontal or vertical.
+// If a parameter is invalid, it returns an empty layout, client error.
+// If no layout is present in query parameters, it returns horizontal unrotated layout.
+func getParamTextLayout(ctx *fiber.Ctx) (img.TextLayout, error) {
+	layout := img.TextLayout{}
+	switch rotateValue := ctx.Query(keyRotate); rotateValue {
+	case "":
+	case rotateDiagonal:
+		layout.Diagonal = true
+	default:
+		rotation, err := strconv.ParseFloat(rotateValue, 64)
+		if err != nil || math.IsInf(rotation, 0) || math.IsNaN(rotation) {
+			return img.TextLayout{}, ErrInvalidParamRotate
+		}
+		layout.Rotation = rotation
+	}
+	switch ctx.Query(keyWriting, writingHorizontal) {
+	case writingHorizontal:
+	case writingVertical:
+		layout.Vertical = true
+	default:
+		return img.TextLayout{}, ErrInvalidParamWriting
+	}
+	return layout, nil
+}
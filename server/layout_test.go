@@ -0,0 +1,58 @@
+package server
+
+import (
+	"image/color"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestHandlerImageTextLayout(t *testing.T) {
+	router := fiber.New()
+	registerRoutes(router)
+
+	tests := []struct {
+		route      string
+		statusCode int
+	}{
+		{route: "/300x200.png?rotate=diagonal", statusCode: 200},
+		{route: "/png?rotate=-45", statusCode: 200},
+		{route: "/png?rotate=90&shape=circle", statusCode: 200},
+		{route: "/200x400.png?writing=vertical&t=%E7%B8%A6%E6%9B%B8%E3%81%8D", statusCode: 200},
+		{route: "/png?writing=horizontal", statusCode: 200},
+		{route: "/png?rotate=sideways", statusCode: 400},
+		{route: "/png?rotate=NaN", statusCode: 400},
+		{route: "/png?writing=mongolian", statusCode: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			res, err := router.Test(httptest.NewRequest(http.MethodGet, tt.route, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.statusCode {
+				t.Errorf("expected status code = %d, actual status code = %d", tt.statusCode, res.StatusCode)
+			}
+		})
+	}
+
+	// Rotated and vertical text runs down the image instead of across it
+	white := color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+	outputs := []struct {
+		route string
+		tall  bool
+	}{
+		{route: "/400x100.png?t=Hello+world&b=000&c=fff", tall: false},
+		{route: "/400x100.png?t=Hello+world&b=000&c=fff&rotate=90", tall: true},
+		{route: "/200x400.png?t=ABCD&b=000&c=fff", tall: false},
+		{route: "/200x400.png?t=ABCD&b=000&c=fff&writing=vertical", tall: true},
+	}
+	for _, tt := range outputs {
+		text := colorBounds(getImage(t, router, tt.route), white).Size()
+		if text.X == 0 || (text.Y > text.X) != tt.tall {
+			t.Errorf("route = %s, expected tall text = %t, actual text size = %v", tt.route, tt.tall, text)
+		}
+	}
+}
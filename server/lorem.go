@@ -0,0 +1,36 @@
+package server
+
+import (
+	"github.com/cod3rboy/yaps/lorem"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Constant for locale query parameter key of generated text
+const keyLocale = "locale"
+
+// Client Errors for generated text
+var (
+	ErrInvalidParamLocale    = fiber.NewError(fiber.ErrBadRequest.Code, "invalid locale ("+keyLocale+") value")
+	ErrInvalidParamGenerator = fiber.NewError(fiber.ErrBadRequest.Code, "invalid text generator ("+keyText+") value")
+)
+
+// getParamGeneratedText returns the text generated for the generator keyword in text, e.g. lorem:12 or name,
+// in the locale read from query parameters and seeded by randomizer.
+//
+// If text is not a generator keyword, it returns text as it is.
+// If the locale or the word count of lorem is invalid, it returns "", client error.
+// If no locale is present in query parameters, the text is generated in the default locale.
+func getParamGeneratedText(ctx *fiber.Ctx, text string, randomizer *colorRandomizer) (string, error) {
+	locale := ctx.Query(keyLocale, lorem.DefaultLocale)
+	if !lorem.IsLocale(locale) {
+		return "", ErrInvalidParamLocale
+	}
+	if !lorem.IsKeyword(text) {
+		return text, nil
+	}
+	generated, err := lorem.Generate(text, locale, randomizer.textRandom())
+	if err != nil {
+		return "", ErrInvalidParamGenerator
+	}
+	return generated, nil
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestHandlerImageGeneratedText(t *testing.T) {
+	router := fiber.New()
+	registerRoutes(router)
+
+	tests := []struct {
+		route      string
+		statusCode int
+		cacheable  bool
+	}{
+		{route: "/400x200.png?t=lorem:12&seed=card-1", statusCode: 200, cacheable: true},
+		{route: "/png?t=name&locale=de&seed=user-7", statusCode: 200, cacheable: true},
+		{route: "/png?t=title&locale=fr&seed=post-3", statusCode: 200, cacheable: true},
+		{route: "/png?t=price&locale=it", statusCode: 200, cacheable: false},
+		{route: "/png?t=Hello&locale=es", statusCode: 200, cacheable: true},
+		{route: "/png?t=%7Bw%7Dx%7Bh%7D", statusCode: 200, cacheable: true},
+		{route: "/png?t=Updated%20%7Bdate%7D", statusCode: 200, cacheable: false},
+		{route: "/png?t=Hello&caption=%7Bdate:Jan%202006%7D", statusCode: 200, cacheable: false},
+		{route: "/png?t=lorem:0", statusCode: 400},
+		{route: "/png?t=lorem:many", statusCode: 400},
+		{route: "/png?t=name&locale=xx", statusCode: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			res, err := router.Test(httptest.NewRequest(http.MethodGet, tt.route, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.statusCode {
+				t.Fatalf("expected status code = %d, actual status code = %d", tt.statusCode, res.StatusCode)
+			}
+			if cacheable := res.Header.Get(fiber.HeaderCacheControl) != "no-store"; tt.statusCode == 200 && cacheable != tt.cacheable {
+				t.Errorf("expected cacheable = %t, actual = %t", tt.cacheable, cacheable)
+			}
+		})
+	}
+
+	read := func(route string) []byte {
+		res, err := router.Test(httptest.NewRequest(http.MethodGet, route, nil), -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return body
+	}
+	if !bytes.Equal(read("/png?t=title&seed=post-1"), read("/png?t=title&seed=post-1")) {
+		t.Errorf("expected same image for same seed")
+	}
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"strconv"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Constants for text overflow query parameter keys
+const (
+	keyMaxLines = "maxlines"
+	keyOverflow = "overflow"
+	keyHyphens  = "hyphens"
+)
+
+// Values of text overflow parameters
+const (
+	hyphensAuto = "auto"
+	hyphensNone = "none"
+)
+
+// Client Errors for text overflow
+var (
+	ErrInvalidParamMaxLines = fiber.NewError(fiber.ErrBadRequest.Code, "invalid max lines ("+keyMaxLines+") value")
+	ErrInvalidParamOverflow = fiber.NewError(fiber.ErrBadRequest.Code, "invalid overflow ("+keyOverflow+") value")
+	ErrInvalidParamHyphens  = fiber.NewError(fiber.ErrBadRequest.Code, "invalid hyphens ("+keyHyphens+") value")
+)
+
+// getParamTextOverflow returns the text overflow read from query parameters.
+//
+// The maximum number of lines is a positive integer and the overflow policy is one of visible, clip, ellipsis or shrink.
+// Hyphens are either auto, which breaks long words with a hyphen, or none.
+// If max lines are present without a policy, the policy is ellipsis.
+// If a parameter is invalid, it returns an empty overflow, client error.
+// If no overflow is present in query parameters, it returns an empty overflow.
+func getParamTextOverflow(ctx *fiber.Ctx) (img.TextOverflow, error) {
+	overflow := img.TextOverflow{}
+	if maxLinesValue := ctx.Query(keyMaxLines); maxLinesValue != "" {
+		maxLines, err := strconv.Atoi(maxLinesValue)
+		if err != nil || maxLines < 1 {
+			return img.TextOverflow{}, ErrInvalidParamMaxLines
+		}
+		overflow.MaxLines = maxLines
+		overflow.Policy = img.OVERFLOW_ELLIPSIS
+	}
+	switch policy := ctx.Query(keyOverflow); policy {
+	case "":
+	case img.OVERFLOW_VISIBLE, img.OVERFLOW_CLIP, img.OVERFLOW_ELLIPSIS, img.OVERFLOW_SHRINK:
+		overflow.Policy = policy
+	default:
+		return img.TextOverflow{}, ErrInvalidParamOverflow
+	}
+	switch ctx.Query(keyHyphens, hyphensNone) {
+	case hyphensNone:
+	case hyphensAuto:
+		overflow.Hyphenate = true
+	default:
+		return img.TextOverflow{}, ErrInvalidParamHyphens
+	}
+	return overflow, nil
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"image/color"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestHandlerImageTextOverflow(t *testing.T) {
+	router := fiber.New()
+	registerRoutes(router)
+
+	tests := []struct {
+		route      string
+		statusCode int
+	}{
+		{route: "/300x200.png?t=Ergonomic%20office%20chair%20with%20lumbar%20support&maxlines=2", statusCode: 200},
+		{route: "/png?maxlines=1&overflow=clip", statusCode: 200},
+		{route: "/png?overflow=shrink&hyphens=auto", statusCode: 200},
+		{route: "/png?overflow=ellipsis&shape=circle", statusCode: 200},
+		{route: "/png?overflow=visible&hyphens=none&rotate=diagonal", statusCode: 200},
+		{route: "/png?maxlines=0", statusCode: 400},
+		{route: "/png?maxlines=two", statusCode: 400},
+		{route: "/png?overflow=scroll", statusCode: 400},
+		{route: "/png?hyphens=manual", statusCode: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			res, err := router.Test(httptest.NewRequest(http.MethodGet, tt.route, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.statusCode {
+				t.Errorf("expected status code = %d, actual status code = %d", tt.statusCode, res.StatusCode)
+			}
+		})
+	}
+
+	// Each line dropped by maxlines shortens the text
+	white := color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+	route := "/300x200.png?t=Ergonomic%20office%20chair%20with%20lumbar%20support&b=000&c=fff"
+	heights := []int{}
+	for _, query := range []string{"", "&maxlines=2", "&maxlines=1"} {
+		heights = append(heights, colorBounds(getImage(t, router, route+query), white).Dy())
+	}
+	if heights[2] == 0 || heights[2] >= heights[1] || heights[1] >= heights[0] {
+		t.Errorf("expected text heights to decrease with maxlines, actual heights = %v", heights)
+	}
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/cod3rboy/yaps/utils/sliceutils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Constants for overlay query parameter keys
+const (
+	keyOverlay         = "overlay"
+	keyOverlayPosition = "overlaypos"
+	keyOverlaySize     = "overlaysize"
+	keyOverlayOpacity  = "overlayopacity"
+	keyOverlayTile     = "overlaytile"
+)
+
+// Client Errors for overlays
+var (
+	ErrInvalidParamOverlay         = fiber.NewError(fiber.ErrBadRequest.Code, "invalid overlay ("+keyOverlay+") value")
+	ErrInvalidParamOverlayPosition = fiber.NewError(fiber.ErrBadRequest.Code, "invalid overlay position ("+keyOverlayPosition+") value")
+	ErrInvalidParamOverlaySize     = fiber.NewError(fiber.ErrBadRequest.Code, "invalid overlay size ("+keyOverlaySize+") value")
+	ErrInvalidParamOverlayOpacity  = fiber.NewError(fiber.ErrBadRequest.Code, "invalid overlay opacity ("+keyOverlayOpacity+") value")
+	ErrInvalidParamOverlayTile     = fiber.NewError(fiber.ErrBadRequest.Code, "invalid overlay tile ("+keyOverlayTile+") value")
+)
+
+// Positions supported by the overlay position parameter
+var overlayPositions = []string{
+	img.POSITION_CENTER,
+	img.POSITION_TOP_LEFT,
+	img.POSITION_TOP,
+	img.POSITION_TOP_RIGHT,
+	img.POSITION_LEFT,
+	img.POSITION_RIGHT,
+	img.POSITION_BOTTOM_LEFT,
+	img.POSITION_BOTTOM,
+	img.POSITION_BOTTOM_RIGHT,
+}
+
+// Default values for overlay parameters
+var (
+	defaultOverlayPosition = img.POSITION_BOTTOM_RIGHT
+	defaultOverlaySize     = 20.0
+	defaultOverlayOpacity  = 1.0
+)
+
+// getParamOverlay returns the overlay read from query parameters.
+//
+// If no overlay is present in query parameters, it returns nil, nil.
+// If a parameter is invalid, it returns nil, client error.
+func getParamOverlay(ctx *fiber.Ctx) (*img.OverlayParams, error) {
+	name := ctx.Query(keyOverlay)
+	if name == "" {
+		return nil, nil
+	}
+	if !img.HasAsset(name) {
+		return nil, ErrInvalidParamOverlay
+	}
+	position := ctx.Query(keyOverlayPosition, defaultOverlayPosition)
+	if !sliceutils.ContainsString(overlayPositions, position) {
+		return nil, ErrInvalidParamOverlayPosition
+	}
+	size, err := parseRangeParam(ctx.Query(keyOverlaySize), defaultOverlaySize, 100)
+	if err != nil || size == 0 {
+		return nil, ErrInvalidParamOverlaySize
+	}
+	opacity, err := parseRangeParam(ctx.Query(keyOverlayOpacity), defaultOverlayOpacity, 1)
+	if err != nil {
+		return nil, ErrInvalidParamOverlayOpacity
+	}
+	tile := false
+	if tileValue := ctx.Query(keyOverlayTile); tileValue != "" {
+		if tile, err = strconv.ParseBool(tileValue); err != nil {
+			return nil, ErrInvalidParamOverlayTile
+		}
+	}
+	return &img.OverlayParams{
+		Asset:    name,
+		Position: position,
+		Size:     size,
+		Opacity:  opacity,
+		Tile:     tile,
+	}, nil
+}
+
+// parseRangeParam parses the value as a number in range [0, max].
+//
+// If the value is empty, it returns defaultValue.
+// If the value is not a number in range, e.g. NaN, it returns 0.0, error.
+func parseRangeParam(value string, defaultValue, max float64) (float64, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0.0, err
+	}
+	if math.IsNaN(number) || number < 0 || number > max {
+		return 0.0, errors.New("value out of range")
+	}
+	return number, nil
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestHandlerImageOverlay(t *testing.T) {
+	dir := t.TempDir()
+	buffer := new(bytes.Buffer)
+	if err := png.Encode(buffer, image.NewNRGBA(image.Rect(0, 0, 10, 10))); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "logo.png"), buffer.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := img.LoadAssets(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	router := fiber.New()
+	registerRoutes(router)
+
+	tests := []struct {
+		route      string
+		statusCode int
+	}{
+		{route: "/png?overlay=logo", statusCode: 200},
+		{route: "/jpg?overlay=logo&overlaypos=top-left&overlaysize=50&overlayopacity=0.3", statusCode: 200},
+		{route: "/300x200.png?overlay=logo&overlaytile=true", statusCode: 200},
+		{route: "/png?overlay=missing", statusCode: 400},
+		{route: "/png?overlay=logo&overlaypos=middle", statusCode: 400},
+		{route: "/png?overlay=logo&overlaysize=0", statusCode: 400},
+		{route: "/png?overlay=logo&overlaysize=101", statusCode: 400},
+		{route: "/png?overlay=logo&overlayopacity=1.5", statusCode: 400},
+		{route: "/png?overlay=logo&overlaytile=sometimes", statusCode: 400},
+		{route: "/png?overlay=logo&overlaysize=NaN", statusCode: 400},
+		{route: "/png?overlay=logo&overlayopacity=NaN", statusCode: 400},
+		{route: "/png?overlay=logo&overlaysize=Inf", statusCode: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			res, err := router.Test(httptest.NewRequest(http.MethodGet, tt.route, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.statusCode {
+				t.Errorf("expected status code = %d, actual status code = %d", tt.statusCode, res.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"errors"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/cod3rboy/yaps/utils"
+	"github.com/cod3rboy/yaps/utils/sliceutils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Constants for photo query parameter keys
+const (
+	keyTag   = "tag"
+	keyIndex = "index"
+	keyCrop  = "crop"
+)
+
+// Client Errors for photos
+var (
+	ErrInvalidParamTag   = fiber.NewError(fiber.ErrNotFound.Code, "no photos found for tag ("+keyTag+") value")
+	ErrInvalidParamIndex = fiber.NewError(fiber.ErrBadRequest.Code, "invalid index ("+keyIndex+") value")
+	ErrInvalidParamCrop  = fiber.NewError(fiber.ErrBadRequest.Code, "invalid crop ("+keyCrop+") value")
+)
+
+// Crop strategies supported by the photo parameter
+var cropStrategies = []string{img.CROP_CENTER, img.CROP_ENTROPY, img.CROP_ATTENTION}
+
+// Default values for photo parameters
+var (
+	defaultPhotoSize = img.Size{Width: 640, Height: 480}
+	defaultPhotoCrop = img.CROP_CENTER
+)
+
+// A PhotoTag stores the number of photos available with a tag.
+type PhotoTag struct {
+	Tag   string `json:"tag"`   // Tag used in tag parameter
+	Count int    `json:"count"` // Number of photos with the tag
+}
+
+// HandlerPhoto is a handler to serve photo placeholder request from the local photo library.
+//
+// The photo is picked by index, or by a hash of the seed, among the photos with the tag.
+// Without an index or seed, a random photo is picked and the response is not cacheable.
+// Text is only written when the text query parameter is present, with its generator and variables expanded like
+// the text of an image.
+func HandlerPhoto(ctx *fiber.Ctx) error {
+	format := ctx.Params(keyFormat)
+	if !sliceutils.ContainsString(SupportedFormats, format) {
+		return ErrUnsupportedFormat
+	}
+	tag := ctx.Query(keyTag)
+	count := img.PhotoCount(tag)
+	if count == 0 {
+		return ErrInvalidParamTag
+	}
+	index, cacheable, err := getParamPhotoIndex(ctx, count)
+	if err != nil {
+		return ErrInvalidParamIndex
+	}
+	crop := ctx.Query(keyCrop, defaultPhotoCrop)
+	if !sliceutils.ContainsString(cropStrategies, crop) {
+		return ErrInvalidParamCrop
+	}
+
+	preset := presets[defaultPresetName]
+	ratio, err := getParamRatio(ctx)
+	if err != nil {
+		return ErrInvalidParamRatio
+	}
+	size, err := getParamSize(ctx, defaultPhotoSize, ratio)
+	if err != nil {
+		return ErrInvalidParamSize
+	}
+	scale, err := getParamScale(ctx, preset.Scale)
+	if err != nil {
+		return ErrInvalidParamScale
+	}
+	font, err := getParamFont(ctx, preset.Font)
+	if err != nil {
+		return ErrInvalidParamFont
+	}
+	randomizer, err := newColorRandomizer(ctx)
+	if err != nil {
+		return ErrInvalidParamScheme
+	}
+	minContrast, err := getParamContrast(ctx)
+	if err != nil {
+		return ErrInvalidParamContrast
+	}
+	overlay, err := getParamOverlay(ctx)
+	if err != nil {
+		return err
+	}
+	if err := authorizeRender(ctx, format, utils.ScaleDimension(size.Width, scale), utils.ScaleDimension(size.Height, scale)); err != nil {
+		return err
+	}
+
+	photo, err := img.OpenPhoto(tag, index)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	// Text color is readable against the average color of the photo
+	average := img.AverageColor(photo)
+	txtColor, err := getParamTextColor(ctx, nil, &average, randomizer, minContrast)
+	if err != nil {
+		return textColorError(err)
+	}
+	effects, err := getParamTextEffects(ctx, txtColor)
+	if err != nil {
+		return err
+	}
+	variables := &textVariables{
+		width:  utils.ScaleDimension(size.Width, scale),
+		height: utils.ScaleDimension(size.Height, scale),
+		format: format,
+		scale:  scale,
+		seed:   ctx.Query(keySeed),
+		now:    time.Now(),
+	}
+	text, err := getParamGeneratedText(ctx, getParamText(ctx, ""), randomizer)
+	if err != nil {
+		return err
+	}
+
+	params := &img.PhotoParams{
+		Format:    format,
+		Size:      size,
+		Photo:     photo,
+		Crop:      crop,
+		Scale:     scale,
+		Text:      variables.expand(text),
+		TextColor: txtColor,
+		Font:      font,
+		Overlay:   overlay,
+		Effects:   effects,
+	}
+
+	result, err := img.GeneratePhoto(params)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	if !cacheable || !randomizer.cacheable() || !variables.cacheable() {
+		ctx.Set(fiber.HeaderCacheControl, "no-store")
+	}
+	return sendResult(ctx, result)
+}
+
+// HandlerPhotos is a handler which responds with the photo tags and their photo counts in JSON.
+func HandlerPhotos(ctx *fiber.Ctx) error {
+	tags := []PhotoTag{}
+	for _, tag := range img.PhotoTags() {
+		tags = append(tags, PhotoTag{Tag: tag, Count: img.PhotoCount(tag)})
+	}
+	return ctx.JSON(tags)
+}
+
+// getParamPhotoIndex returns the index of the photo among count photos read from query parameters,
+// and whether the same photo is picked for the same request.
+//
+// If the index is not a non-negative number, it returns 0, false, error.
+// An index larger than count wraps around. Without an index, the index is derived from the seed.
+// If neither index nor seed is present in query parameters, it returns a random index.
+func getParamPhotoIndex(ctx *fiber.Ctx, count int) (int, bool, error) {
+	if indexValue := ctx.Query(keyIndex); indexValue != "" {
+		index, err := strconv.Atoi(indexValue)
+		if err != nil {
+			return 0, false, err
+		}
+		if index < 0 {
+			return 0, false, errors.New("index must not be negative")
+		}
+		return index % count, true, nil
+	}
+	if seed := ctx.Query(keySeed); seed != "" {
+		hash := fnv.New32a()
+		hash.Write([]byte(seed))
+		return int(hash.Sum32() % uint32(count)), true, nil
+	}
+	return rand.Intn(count), false, nil
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestHandlerPhoto(t *testing.T) {
+	dir := t.TempDir()
+	for i, name := range []string{"nature/lake.png", "nature/tree.png", "people/jane.png"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		photo := image.NewNRGBA(image.Rect(0, 0, 80, 60))
+		for p := range photo.Pix {
+			photo.Pix[p] = uint8(i * 100)
+		}
+		photo.SetNRGBA(0, 0, color.NRGBA{A: 0xFF})
+		buffer := new(bytes.Buffer)
+		if err := png.Encode(buffer, photo); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, buffer.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := img.LoadPhotos(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer img.LoadPhotos(t.TempDir())
+
+	router := fiber.New()
+	registerRoutes(router)
+	get := func(route string) *http.Response {
+		res, err := router.Test(httptest.NewRequest(http.MethodGet, route, nil), -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	read := func(res *http.Response) []byte {
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return body
+	}
+
+	res := get("/photo/png?tag=nature&s=40x40&crop=entropy&index=1&t=Hello")
+	if res.StatusCode != 200 {
+		t.Fatalf("expected status code = 200, actual status code = %d", res.StatusCode)
+	}
+	decoded, err := png.Decode(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := decoded.Bounds().Size(); size.X != 40 || size.Y != 40 {
+		t.Errorf("expected size = 40x40, actual size = %dx%d", size.X, size.Y)
+	}
+
+	if !bytes.Equal(read(get("/photo/png?seed=jane")), read(get("/photo/png?seed=jane"))) {
+		t.Errorf("expected same photo for same seed")
+	}
+	if !bytes.Equal(read(get("/photo/png?tag=nature&index=0")), read(get("/photo/png?tag=nature&index=2"))) {
+		t.Errorf("expected index to wrap around")
+	}
+	if res := get("/photo/png"); res.Header.Get(fiber.HeaderCacheControl) != "no-store" {
+		t.Errorf("expected random photo not to be cached")
+	}
+	if !bytes.Equal(read(get("/photo/png?index=0&s=80x60&t={w}x{h}")), read(get("/photo/png?index=0&s=80x60&t=80x60"))) {
+		t.Errorf("expected variables to be expanded in photo text")
+	}
+	if bytes.Equal(read(get("/photo/png?index=0&t=lorem:3&seed=jane")), read(get("/photo/png?index=0&t=lorem:3&seed=joe"))) {
+		t.Errorf("expected generator to be expanded in photo text")
+	}
+	if res := get("/photo/png?index=0&t={date}"); res.Header.Get(fiber.HeaderCacheControl) != "no-store" {
+		t.Errorf("expected photo with dated text not to be cached")
+	}
+
+	tests := []struct {
+		route      string
+		statusCode int
+	}{
+		{route: "/photo/png?tag=cars", statusCode: 404},
+		{route: "/photo/png?index=-1", statusCode: 400},
+		{route: "/photo/png?crop=smart", statusCode: 400},
+		{route: "/photo/gif", statusCode: 400},
+		{route: "/photo/png?tag=nature&t=Hello&textbox=pill&stroke=1", statusCode: 200},
+		{route: "/photo/png?t=Hello&textshadow=blue", statusCode: 400},
+		{route: "/photo/png?t=lorem&locale=xx", statusCode: 400},
+	}
+	for _, tt := range tests {
+		if res := get(tt.route); res.StatusCode != tt.statusCode {
+			t.Errorf("route = %s, expected status code = %d, actual status code = %d", tt.route, tt.statusCode, res.StatusCode)
+		}
+	}
+
+	tags := []PhotoTag{}
+	if err := json.NewDecoder(get("/photos").Body).Decode(&tags); err != nil {
+		t.Fatal(err)
+	}
+	expected := []PhotoTag{{Tag: "nature", Count: 2}, {Tag: "people", Count: 1}}
+	if len(tags) != len(expected) || tags[0] != expected[0] || tags[1] != expected[1] {
+		t.Errorf("expected tags = %v, actual tags = %v", expected, tags)
+	}
+}
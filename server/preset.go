@@ -0,0 +1,202 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cod3rboy/yaps/img"
+)
+
+// A Preset represents a named set of image parameters.
+//
+// Query parameters of a request override the values of its preset.
+type Preset struct {
+	Size            img.Size  // Image size
+	BackgroundColor img.Color // Color to use for background
+	TextColor       img.Color // Color to use for text
+	AutoTextColor   bool      // Whether text color is computed from background color, see [img.AccessibleTextColor]
+	Scale           float64   // Value by which to scale Size
+	Font            string    // Name of the font to write text
+	Text            string    // Text to write on the image, empty for the default label, see [LoadLabelFormat]
+}
+
+// Name of the preset used when no preset is requested.
+//
+// Presets defined in presets file start with the values of this preset.
+const defaultPresetName = "default"
+
+// Constants for preset property names
+const (
+	presetSize      = "size"
+	presetBgColor   = "bg"
+	presetTextColor = "fg"
+	presetScale     = "scale"
+	presetFont      = "font"
+	presetText      = "text"
+)
+
+// Built-in default preset, can be overridden in presets file
+var builtinDefaultPreset = Preset{
+	Size: img.Size{Width: 100, Height: 100},
+	BackgroundColor: img.Color{
+		// Light Gray (#cccccc)
+		R: 0xCC,
+		G: 0xCC,
+		B: 0xCC,
+	},
+	TextColor: img.Color{
+		// Dark Gray (#969696)
+		R: 0x96,
+		G: 0x96,
+		B: 0x96,
+	},
+	Scale: 1.0,
+	Font:  img.DefaultFont,
+}
+
+// Configured presets by name
+var presets = map[string]*Preset{
+	defaultPresetName: &builtinDefaultPreset,
+}
+
+// LoadPresets reads the presets file at path and makes its presets available to the image handlers.
+//
+// Each line of the file defines a preset in format <name> = <properties> where properties
+// are comma-separated and each property is a name followed by its value e.g.
+//
+//	avatar = 128x128, bg 334155, fg f8fafc, font Inter-Bold
+//
+// The size property name can be omitted and fg can be auto for a text color readable against bg.
+// Lines starting with ; or # are comments.
+// A preset named default overrides the built-in default values.
+//
+// If an error occurs while reading or parsing the file, it returns that error.
+func LoadPresets(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	parsed, err := parsePresets(file)
+	if err != nil {
+		return err
+	}
+	presets = parsed
+	return nil
+}
+
+// parsePresets parses the presets definitions read from reader.
+//
+// If an error occurs while reading or parsing, it returns nil, error.
+func parsePresets(reader io.Reader) (map[string]*Preset, error) {
+	specs := map[string]string{}
+	names := []string{}
+	scanner := bufio.NewScanner(reader)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, spec, found := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid preset definition at line %d", lineNumber)
+		}
+		if _, exists := specs[name]; exists {
+			return nil, fmt.Errorf("duplicate preset %s at line %d", name, lineNumber)
+		}
+		specs[name] = spec
+		names = append(names, name)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Default preset is parsed first as other presets start with its values
+	defaultPreset := &builtinDefaultPreset
+	if spec, exists := specs[defaultPresetName]; exists {
+		preset, err := parsePreset(spec, defaultPreset)
+		if err != nil {
+			return nil, fmt.Errorf("invalid preset %s: %w", defaultPresetName, err)
+		}
+		defaultPreset = preset
+	}
+	parsed := map[string]*Preset{defaultPresetName: defaultPreset}
+	for _, name := range names {
+		if name == defaultPresetName {
+			continue
+		}
+		preset, err := parsePreset(specs[name], defaultPreset)
+		if err != nil {
+			return nil, fmt.Errorf("invalid preset %s: %w", name, err)
+		}
+		parsed[name] = preset
+	}
+	return parsed, nil
+}
+
+// parsePreset returns the preset parsed from comma-separated properties in spec.
+// Properties absent from spec are copied from base.
+//
+// If an error occurs while parsing, it returns nil, error.
+func parsePreset(spec string, base *Preset) (*Preset, error) {
+	preset := *base
+	for _, property := range strings.Split(spec, ",") {
+		property = strings.TrimSpace(property)
+		if property == "" {
+			continue
+		}
+		name, value, found := strings.Cut(property, " ")
+		if !found {
+			// Bare value is the size
+			name, value = presetSize, property
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(name) {
+		case presetSize:
+			size, err := parseSize(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %s", presetSize, value)
+			}
+			preset.Size = *size
+		case presetBgColor:
+			color, err := parseColor(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %s", presetBgColor, value)
+			}
+			preset.BackgroundColor = *color
+		case presetTextColor:
+			if value == colorAuto {
+				preset.AutoTextColor = true
+				continue
+			}
+			color, err := parseColor(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %s", presetTextColor, value)
+			}
+			preset.TextColor = *color
+			preset.AutoTextColor = false
+		case presetScale:
+			scale, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %s", presetScale, value)
+			}
+			preset.Scale = scale
+		case presetFont:
+			if !img.HasFont(value) {
+				return nil, fmt.Errorf("invalid %s %s", presetFont, value)
+			}
+			preset.Font = value
+		case presetText:
+			preset.Text = value
+		default:
+			return nil, fmt.Errorf("unknown property %s", name)
+		}
+	}
+	return &preset, nil
+}
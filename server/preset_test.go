@@ -0,0 +1,145 @@
+package server
+
+import (
+	"image"
+	"image/color"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/gofiber/fiber/v2"
+)
+
+const testPresets = `
+; Comment line
+default = 200x100, bg 000, fg fff
+avatar = 128x128, bg 334155, fg f8fafc, font Go-Bold
+# Another comment
+banner = size 728x90, scale 2, text Ad Space
+tile = bg 1e3a8a, fg auto
+`
+
+func TestParsePresets(t *testing.T) {
+	parsed, err := parsePresets(strings.NewReader(testPresets))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		want Preset
+	}{
+		{
+			name: "default",
+			want: Preset{Size: img.Size{Width: 200, Height: 100}, BackgroundColor: img.Color{}, TextColor: img.Color{R: 0xFF, G: 0xFF, B: 0xFF}, Scale: 1, Font: img.DefaultFont},
+		},
+		{
+			name: "avatar",
+			want: Preset{Size: img.Size{Width: 128, Height: 128}, BackgroundColor: img.Color{R: 0x33, G: 0x41, B: 0x55}, TextColor: img.Color{R: 0xF8, G: 0xFA, B: 0xFC}, Scale: 1, Font: "Go-Bold"},
+		},
+		{
+			name: "banner",
+			want: Preset{Size: img.Size{Width: 728, Height: 90}, BackgroundColor: img.Color{}, TextColor: img.Color{R: 0xFF, G: 0xFF, B: 0xFF}, Scale: 2, Font: img.DefaultFont, Text: "Ad Space"},
+		},
+		{
+			name: "tile",
+			want: Preset{Size: img.Size{Width: 200, Height: 100}, BackgroundColor: img.Color{R: 0x1E, G: 0x3A, B: 0x8A}, TextColor: img.Color{R: 0xFF, G: 0xFF, B: 0xFF}, AutoTextColor: true, Scale: 1, Font: img.DefaultFont},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, exists := parsed[tt.name]
+			if !exists {
+				t.Fatalf("preset %s not found", tt.name)
+			}
+			if *got != tt.want {
+				t.Errorf("preset = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePresetsInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "Missing equals", content: "avatar 128x128"},
+		{name: "Missing name", content: "= 128x128"},
+		{name: "Duplicate preset", content: "a = 10\na = 20"},
+		{name: "Invalid size", content: "a = 10y20"},
+		{name: "Invalid color", content: "a = bg FFFF"},
+		{name: "Invalid scale", content: "a = scale two"},
+		{name: "Unknown font", content: "a = font Comic-Sans"},
+		{name: "Unknown property", content: "a = border 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parsePresets(strings.NewReader(tt.content)); err == nil {
+				t.Errorf("expected error for %q", tt.content)
+			}
+		})
+	}
+}
+
+func TestHandlerPreset(t *testing.T) {
+	parsed, err := parsePresets(strings.NewReader(testPresets))
+	if err != nil {
+		t.Fatal(err)
+	}
+	builtinPresets := presets
+	presets = parsed
+	defer func() { presets = builtinPresets }()
+
+	router := fiber.New()
+	registerRoutes(router)
+
+	tests := []struct {
+		route              string
+		expectedStatusCode int
+	}{
+		{route: "/png?preset=avatar", expectedStatusCode: 200},
+		{route: "/png?preset=avatar&s=64&b=F00&f=Go-Mono", expectedStatusCode: 200},
+		{route: "/preset/banner.jpg", expectedStatusCode: 200},
+		{route: "/preset/banner.gif", expectedStatusCode: 400},
+		{route: "/png?preset=unknown", expectedStatusCode: 400},
+		{route: "/preset/unknown.png", expectedStatusCode: 400},
+		{route: "/png?f=Comic-Sans", expectedStatusCode: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			res, err := router.Test(httptest.NewRequest(http.MethodGet, tt.route, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.expectedStatusCode {
+				t.Errorf("expected status code = %d, actual status code = %d", tt.expectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+
+	// Preset size, scale and colors apply to the image, and query parameters override them
+	outputs := []struct {
+		route      string
+		size       image.Point
+		background color.Color
+		text       color.Color
+	}{
+		{route: "/png?preset=avatar", size: image.Pt(128, 128), background: color.RGBA{R: 0x33, G: 0x41, B: 0x55, A: 0xFF}, text: color.RGBA{R: 0xF8, G: 0xFA, B: 0xFC, A: 0xFF}},
+		{route: "/png?preset=avatar&s=64&b=F00", size: image.Pt(64, 64), background: color.RGBA{R: 0xFF, A: 0xFF}, text: color.RGBA{R: 0xF8, G: 0xFA, B: 0xFC, A: 0xFF}},
+		{route: "/preset/banner.png", size: image.Pt(1456, 180), background: color.RGBA{A: 0xFF}, text: color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}},
+	}
+	for _, tt := range outputs {
+		decoded := getImage(t, router, tt.route)
+		if size := decoded.Bounds().Size(); size != tt.size {
+			t.Errorf("route = %s, expected size = %v, actual size = %v", tt.route, tt.size, size)
+		}
+		if background := color.RGBAModel.Convert(decoded.At(0, 0)); background != tt.background {
+			t.Errorf("route = %s, expected background color = %v, actual background color = %v", tt.route, tt.background, background)
+		}
+		if colorBounds(decoded, tt.text).Empty() {
+			t.Errorf("route = %s, expected text of color %v", tt.route, tt.text)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"errors"
+	"hash/fnv"
+	"math/rand"
+	"time"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Constant for color scheme query parameter key
+const keyScheme = "scheme"
+
+// Value of color parameters which picks a random color
+const colorRandom = "random"
+
+// Client Errors for random colors
+var (
+	ErrInvalidParamScheme = fiber.NewError(fiber.ErrBadRequest.Code, "invalid color scheme ("+keyScheme+") value")
+)
+
+// Default color scheme of random background colors when no random palette is configured
+const defaultColorScheme = img.SCHEME_PASTEL
+
+// Palette of random background colors, empty for colors generated by color scheme, see [LoadRandomPalette]
+var randomPalette = []img.Color{}
+
+// LoadRandomPalette sets the palette of random background colors to comma-separated hexadecimal colors in value.
+//
+// If an error occurs while parsing any color, the palette is not changed and it returns that error.
+func LoadRandomPalette(value string) error {
+	palette, err := parsePalette(value)
+	if err != nil {
+		return err
+	}
+	randomPalette = palette
+	return nil
+}
+
+// A colorRandomizer picks the colors for the random value of color parameters, and seeds the generated text.
+type colorRandomizer struct {
+	random  *rand.Rand
+	seed    int64
+	words   *rand.Rand       // Source of generated text, see [colorRandomizer.textRandom]
+	scheme  *img.ColorScheme // Scheme of background colors, nil for colors from palette
+	palette []img.Color      // Palette of background colors
+	seeded  bool             // Whether the colors and text are deterministic
+	used    bool             // Whether any random color or text is picked
+}
+
+// newColorRandomizer returns a [colorRandomizer] for the seed and scheme read from query parameters.
+//
+// With a seed, the same seed always picks the same colors. Without a seed, the colors are different for each request.
+// If no scheme is present in query parameters, the colors are picked from the random palette or, when it is empty,
+// generated by the default scheme.
+//
+// If the scheme does not exist, it returns nil, error.
+func newColorRandomizer(ctx *fiber.Ctx) (*colorRandomizer, error) {
+	randomizer := new(colorRandomizer)
+
+	seed := time.Now().UnixNano()
+	if seedValue := ctx.Query(keySeed); seedValue != "" {
+		hash := fnv.New64a()
+		hash.Write([]byte(seedValue))
+		seed = int64(hash.Sum64())
+		randomizer.seeded = true
+	}
+	randomizer.seed = seed
+	randomizer.random = rand.New(rand.NewSource(seed))
+
+	schemeValue := ctx.Query(keyScheme)
+	if schemeValue == "" && len(randomPalette) > 0 {
+		randomizer.palette = randomPalette
+		return randomizer, nil
+	}
+	if schemeValue == "" {
+		schemeValue = defaultColorScheme
+	}
+	scheme, exists := img.ColorSchemes[schemeValue]
+	if !exists {
+		return nil, errors.New("color scheme not found with name " + schemeValue)
+	}
+	randomizer.scheme = &scheme
+	return randomizer, nil
+}
+
+// background returns a random background color.
+func (r *colorRandomizer) background() *img.Color {
+	r.used = true
+	var color img.Color
+	if r.scheme != nil {
+		color = r.scheme.Color(r.random)
+	} else {
+		color = r.palette[r.random.Intn(len(r.palette))]
+	}
+	return &color
+}
+
+// text returns a random text color which has at least minContrast ratio against background.
+//
+// If no color has minContrast ratio against background, it returns nil, [img.ErrContrastRatio].
+func (r *colorRandomizer) text(background *img.Color, minContrast float64) (*img.Color, error) {
+	r.used = true
+	color, err := img.RandomTextColor(r.random, *background, minContrast)
+	if err != nil {
+		return nil, err
+	}
+	return &color, nil
+}
+
+// textRandom returns the random source of generated text with the seed of the randomizer.
+//
+// The text source is separate from the colors, so the same seed generates the same text with or without random colors.
+// The same source is returned for all the generated text of an image, so each text is different.
+func (r *colorRandomizer) textRandom() *rand.Rand {
+	r.used = true
+	if r.words == nil {
+		r.words = rand.New(rand.NewSource(r.seed))
+	}
+	return r.words
+}
+
+// cacheable returns false if the colors or text picked by the randomizer are different for each request otherwise it returns true.
+func (r *colorRandomizer) cacheable() bool {
+	return r.seeded || !r.used
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"bytes"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestHandlerImageRandom(t *testing.T) {
+	router := fiber.New()
+	registerRoutes(router)
+
+	get := func(route string) *http.Response {
+		res, err := router.Test(httptest.NewRequest(http.MethodGet, route, nil), -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	read := func(res *http.Response) []byte {
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return body
+	}
+
+	first, second := get("/png?b=random&c=random&seed=tile-1"), get("/png?b=random&c=random&seed=tile-1")
+	if first.StatusCode != 200 || second.StatusCode != 200 {
+		t.Fatalf("expected status code = 200, actual status codes = %d, %d", first.StatusCode, second.StatusCode)
+	}
+	if first.Header.Get(fiber.HeaderCacheControl) != "" {
+		t.Errorf("expected seeded image to be cacheable")
+	}
+	if !bytes.Equal(read(first), read(second)) {
+		t.Errorf("expected same image for same seed")
+	}
+	if bytes.Equal(read(get("/png?b=random&seed=tile-1")), read(get("/png?b=random&seed=tile-2"))) {
+		t.Errorf("expected different images for different seeds")
+	}
+
+	unseeded := get("/png?b=random")
+	if unseeded.Header.Get(fiber.HeaderCacheControl) != "no-store" {
+		t.Errorf("expected unseeded random image not to be cached")
+	}
+
+	for _, route := range []string{"/png?b=random&scheme=dark", "/300x200/random/random.png?seed=1", "/avatar/png?name=Jane&b=random", "/identicon/png?seed=jane&b=random"} {
+		if res := get(route); res.StatusCode != 200 {
+			t.Errorf("route = %s, expected status code = 200, actual status code = %d", route, res.StatusCode)
+		}
+	}
+	if res := get("/png?b=random&scheme=neon"); res.StatusCode != 400 {
+		t.Errorf("expected status code = 400 for unknown scheme, actual status code = %d", res.StatusCode)
+	}
+}
+
+func TestHandlerImageRandomPalette(t *testing.T) {
+	palette := []img.Color{{R: 0x12, G: 0x34, B: 0x56}}
+	randomPalette = palette
+	defer func() { randomPalette = []img.Color{} }()
+
+	router := fiber.New()
+	registerRoutes(router)
+
+	res, err := router.Test(httptest.NewRequest(http.MethodGet, "/png?b=random&t=+", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := png.Decode(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, g, b, _ := decoded.At(0, 0).RGBA()
+	if actual := (img.Color{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8)}); actual != palette[0] {
+		t.Errorf("expected background color = %+v, actual background color = %+v", palette[0], actual)
+	}
+}
@@ -2,16 +2,22 @@
 package server
 
 import (
+	"log"
 	"strconv"
 	"strings"
 
+	"github.com/cod3rboy/yaps/apikey"
 	"github.com/cod3rboy/yaps/config"
+	"github.com/cod3rboy/yaps/img"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 )
 
 // SetupAndListen fires up a http server to handle incoming requests for image generation.
 //
+// When a sign secret is configured, only signed urls are accepted.
+// When an api keys file is configured, requests must carry one of its api keys.
+//
 // For supported image formats, see [SupportedFormats].
 func SetupAndListen() {
 	app := fiber.New()
@@ -19,7 +25,94 @@ func SetupAndListen() {
 		AllowOrigins: config.AllowOrigins(),
 		AllowMethods: config.AllowMethods(),
 	}))
+	if dir := config.FontsDir(); dir != "" {
+		if err := img.LoadFonts(dir); err != nil {
+			log.Fatalf("failed to load fonts: %v", err)
+		}
+	}
+	if names := config.FallbackFonts(); names != "" {
+		if err := img.SetFallbackFonts(strings.Split(names, ",")); err != nil {
+			log.Fatalf("failed to load fallback fonts: %v", err)
+		}
+	}
+	if dir := config.AssetsDir(); dir != "" {
+		if err := img.LoadAssets(dir); err != nil {
+			log.Fatalf("failed to load assets: %v", err)
+		}
+	}
+	if dir := config.PhotosDir(); dir != "" {
+		if err := img.LoadPhotos(dir); err != nil {
+			log.Fatalf("failed to load photos: %v", err)
+		}
+	}
+	if dir := config.TemplatesDir(); dir != "" {
+		if err := img.LoadTemplates(dir); err != nil {
+			log.Fatalf("failed to load templates: %v", err)
+		}
+	}
+	if path := config.PresetsFile(); path != "" {
+		if err := LoadPresets(path); err != nil {
+			log.Fatalf("failed to load presets: %v", err)
+		}
+	}
+	if palette := config.AvatarPalette(); palette != "" {
+		if err := LoadAvatarPalette(palette); err != nil {
+			log.Fatalf("failed to load avatar palette: %v", err)
+		}
+	}
+	if palette := config.RandomPalette(); palette != "" {
+		if err := LoadRandomPalette(palette); err != nil {
+			log.Fatalf("failed to load random palette: %v", err)
+		}
+	}
+	if err := LoadContrastLevel(config.ContrastLevel()); err != nil {
+		log.Fatalf("failed to load contrast level: %v", err)
+	}
+	if err := LoadLabelFormat(config.LabelFormat()); err != nil {
+		log.Fatalf("failed to load label format: %v", err)
+	}
 	router := app.Group(config.PathPrefix())
-	router.Get("/:format<regex("+strings.Join(SupportedFormats, "|")+")>", HandlerImage)
+	if secret := config.SignSecret(); secret != "" {
+		router.Use(NewHandlerSignature(secret))
+	}
+	if path := config.APIKeysFile(); path != "" {
+		registry, err := apikey.LoadRegistry(path)
+		if err != nil {
+			log.Fatalf("failed to load api keys: %v", err)
+		}
+		store, err := apikey.OpenStore(config.UsageStore())
+		if err != nil {
+			log.Fatalf("failed to open usage store: %v", err)
+		}
+		defer store.Close()
+		router.Use(NewHandlerAPIKey(registry, store))
+		router.Get("/usage", HandlerUsage)
+	}
+	registerRoutes(router)
 	app.Listen(config.Host() + ":" + strconv.Itoa(config.Port()))
 }
+
+// registerRoutes registers all the image generation routes on the router.
+//
+// Path-style routes are registered from the most specific to the least specific
+// because their parameters are not constrained and would otherwise shadow each other.
+func registerRoutes(router fiber.Router) {
+	router.Get("/:"+keyFormat+"<regex(^("+strings.Join(SupportedFormats, "|")+")$)>", HandlerImage)
+	router.Get("/preset/:"+keyPreset+".:"+keyFormat, HandlerImage)
+	router.Get("/sizes", HandlerSizes)
+	router.Get("/avatar/:"+keyFormat, HandlerAvatar)
+	router.Get("/identicon/:"+keyFormat, HandlerIdenticon)
+	router.Get("/blurhash/:"+keyFormat, HandlerBlurHash)
+	router.Get("/thumbhash/:"+keyFormat, HandlerThumbHash)
+	router.Get("/hash", HandlerHash)
+	router.Get("/photo/:"+keyFormat, HandlerPhoto)
+	router.Get("/photos", HandlerPhotos)
+	router.Get("/t/:"+keyTemplate+".:"+keyFormat, HandlerTemplate)
+	router.Get("/card/:"+keyFormat, HandlerCard)
+	// Path-style routes e.g. /300x200/ff0000/ffffff.png
+	router.Get("/:"+keySize+"/:"+keyBgColor+"/:"+keyTextColor+".:"+keyFormat, HandlerPathImage)
+	router.Get("/:"+keySize+"/:"+keyBgColor+"/:"+keyTextColor, HandlerPathImage)
+	router.Get("/:"+keySize+"/:"+keyFormat, HandlerPathImage)
+	router.Get("/:"+keySize+".:"+keyFormat, HandlerPathImage)
+	router.Get("/:"+keySize, HandlerPathImage)
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"github.com/cod3rboy/yaps/img"
+	"github.com/cod3rboy/yaps/utils/sliceutils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Constant for matte color query parameter key
+const keyMatte = "matte"
+
+// Client Errors for image shapes
+var (
+	ErrInvalidParamMatte = fiber.NewError(fiber.ErrBadRequest.Code, "invalid matte color ("+keyMatte+") value")
+)
+
+// Shapes supported by the shape parameter of images
+var imageShapes = []string{img.SHAPE_CIRCLE, img.SHAPE_ELLIPSE, img.SHAPE_HEXAGON, img.SHAPE_SQUIRCLE}
+
+// getParamShape returns the image shape and the color outside the shape and the frame read from query parameters.
+//
+// If no shape is present in query parameters, the shape is "".
+// If no matte color is present in query parameters, the matte color is nil.
+// If a parameter is invalid, it returns "", nil, client error.
+func getParamShape(ctx *fiber.Ctx) (string, *img.Color, error) {
+	shape := ctx.Query(keyShape)
+	if shape != "" && !sliceutils.ContainsString(imageShapes, shape) {
+		return "", nil, ErrInvalidParamShape
+	}
+	matteValue := ctx.Query(keyMatte)
+	if matteValue == "" {
+		return shape, nil, nil
+	}
+	matte, err := parseColor(matteValue)
+	if err != nil {
+		return "", nil, ErrInvalidParamMatte
+	}
+	return shape, matte, nil
+}
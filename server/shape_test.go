@@ -0,0 +1,61 @@
+package server
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestHandlerImageShape(t *testing.T) {
+	router := fiber.New()
+	registerRoutes(router)
+
+	tests := []struct {
+		route      string
+		statusCode int
+	}{
+		{route: "/png?shape=circle", statusCode: 200},
+		{route: "/300x200.png?shape=ellipse&matte=fff", statusCode: 200},
+		{route: "/jpg?shape=hexagon", statusCode: 200},
+		{route: "/png?shape=squircle&t=Profile+picture", statusCode: 200},
+		{route: "/png?shape=star", statusCode: 400},
+		{route: "/png?shape=circle&matte=white", statusCode: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			res, err := router.Test(httptest.NewRequest(http.MethodGet, tt.route, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.statusCode {
+				t.Errorf("expected status code = %d, actual status code = %d", tt.statusCode, res.StatusCode)
+			}
+		})
+	}
+
+	res, err := router.Test(httptest.NewRequest(http.MethodGet, "/png?shape=circle", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := png.Decode(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, a := decoded.At(0, 0).RGBA(); a != 0 {
+		t.Errorf("expected transparent corner, actual alpha = %d", a>>8)
+	}
+
+	// Matte color fills the area outside the ellipse, which touches the image edges
+	decoded = getImage(t, router, "/300x200.png?b=f00&shape=ellipse&matte=fff")
+	if matte := color.RGBAModel.Convert(decoded.At(0, 0)); matte != (color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}) {
+		t.Errorf("expected white matte in the corner, actual color = %v", matte)
+	}
+	if bounds := colorBounds(decoded, color.RGBA{R: 0xFF, A: 0xFF}); bounds != image.Rect(1, 1, 299, 199) {
+		t.Errorf("expected ellipse = (1,1)-(299,199), actual = %v", bounds)
+	}
+}
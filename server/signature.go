@@ -0,0 +1,39 @@
+package server
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/cod3rboy/yaps/sign"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Client Errors for signed urls
+var (
+	ErrMissingSignature = fiber.NewError(fiber.StatusForbidden, "missing signature ("+sign.KeySignature+")")
+	ErrInvalidSignature = fiber.NewError(fiber.StatusForbidden, "invalid signature ("+sign.KeySignature+")")
+	ErrExpiredSignature = fiber.NewError(fiber.StatusForbidden, "signature expired ("+sign.KeyExpiry+")")
+)
+
+// NewHandlerSignature returns a handler which passes on only the requests with url signed by secret.
+//
+// Unsigned, tampered and expired requests are rejected with 403 status code.
+// For the signing scheme, see [sign.SignURL].
+func NewHandlerSignature(secret string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		query, err := url.ParseQuery(string(ctx.Request().URI().QueryString()))
+		if err != nil {
+			return ErrInvalidSignature
+		}
+		switch sign.Verify(secret, ctx.Path(), query, time.Now()) {
+		case nil:
+			return ctx.Next()
+		case sign.ErrMissingSignature:
+			return ErrMissingSignature
+		case sign.ErrExpired:
+			return ErrExpiredSignature
+		default:
+			return ErrInvalidSignature
+		}
+	}
+}
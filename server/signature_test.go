@@ -0,0 +1,51 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cod3rboy/yaps/sign"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestHandlerSignature(t *testing.T) {
+	const secret = "s3cr3t"
+	router := fiber.New()
+	router.Use(NewHandlerSignature(secret))
+	registerRoutes(router)
+
+	mustSign := func(rawURL string, expiry time.Time) string {
+		signed, err := sign.SignURL(secret, rawURL, expiry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	tests := []struct {
+		name               string
+		route              string
+		expectedStatusCode int
+	}{
+		{name: "Unsigned", route: "/png?s=100", expectedStatusCode: 403},
+		{name: "Signed", route: mustSign("/png?s=100", time.Time{}), expectedStatusCode: 200},
+		{name: "Signed path-style", route: mustSign("/100x50/ff0000/ffffff.png", time.Time{}), expectedStatusCode: 200},
+		{name: "Signed with expiry", route: mustSign("/png?s=100", time.Now().Add(time.Hour)), expectedStatusCode: 200},
+		{name: "Expired", route: mustSign("/png?s=100", time.Now().Add(-time.Hour)), expectedStatusCode: 403},
+		{name: "Wrong secret", route: func() string { s, _ := sign.SignURL("other", "/png?s=100", time.Time{}); return s }(), expectedStatusCode: 403},
+		{name: "Tampered", route: mustSign("/png?s=100", time.Time{}) + "&t=Hacked", expectedStatusCode: 403},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := router.Test(httptest.NewRequest(http.MethodGet, tt.route, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.expectedStatusCode {
+				t.Errorf("expected status code = %d, actual status code = %d", tt.expectedStatusCode, res.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Categories of size aliases
+const (
+	sizeCategoryAd     = "ad"
+	sizeCategorySocial = "social"
+	sizeCategoryVideo  = "video"
+	sizeCategoryDevice = "device"
+)
+
+// A SizeAlias represents a well-known image size which can be used in place of dimensions in size parameter.
+type SizeAlias struct {
+	Name     string `json:"name"`     // Alias used in size parameter
+	Category string `json:"category"` // Category of the alias e.g. ad, social, video or device
+	Width    int    `json:"width"`    // Width of the image
+	Height   int    `json:"height"`   // Height of the image
+}
+
+// Built-in size aliases in the order they are listed
+var sizeAliases = []SizeAlias{
+	// IAB ad units
+	{Name: "leaderboard", Category: sizeCategoryAd, Width: 728, Height: 90},
+	{Name: "large-leaderboard", Category: sizeCategoryAd, Width: 970, Height: 90},
+	{Name: "billboard", Category: sizeCategoryAd, Width: 970, Height: 250},
+	{Name: "banner", Category: sizeCategoryAd, Width: 468, Height: 60},
+	{Name: "mobile-banner", Category: sizeCategoryAd, Width: 320, Height: 50},
+	{Name: "mrec", Category: sizeCategoryAd, Width: 300, Height: 250},
+	{Name: "large-rectangle", Category: sizeCategoryAd, Width: 336, Height: 280},
+	{Name: "square", Category: sizeCategoryAd, Width: 250, Height: 250},
+	{Name: "skyscraper", Category: sizeCategoryAd, Width: 120, Height: 600},
+	{Name: "wide-skyscraper", Category: sizeCategoryAd, Width: 160, Height: 600},
+	{Name: "half-page", Category: sizeCategoryAd, Width: 300, Height: 600},
+	// Social media formats
+	{Name: "og", Category: sizeCategorySocial, Width: 1200, Height: 630},
+	{Name: "twitter-card", Category: sizeCategorySocial, Width: 1200, Height: 628},
+	{Name: "twitter-header", Category: sizeCategorySocial, Width: 1500, Height: 500},
+	{Name: "facebook-cover", Category: sizeCategorySocial, Width: 820, Height: 312},
+	{Name: "linkedin-post", Category: sizeCategorySocial, Width: 1200, Height: 627},
+	{Name: "linkedin-banner", Category: sizeCategorySocial, Width: 1584, Height: 396},
+	{Name: "instagram-post", Category: sizeCategorySocial, Width: 1080, Height: 1080},
+	{Name: "instagram-portrait", Category: sizeCategorySocial, Width: 1080, Height: 1350},
+	{Name: "instagram-story", Category: sizeCategorySocial, Width: 1080, Height: 1920},
+	{Name: "youtube-thumbnail", Category: sizeCategorySocial, Width: 1280, Height: 720},
+	// Video resolutions
+	{Name: "240p", Category: sizeCategoryVideo, Width: 426, Height: 240},
+	{Name: "360p", Category: sizeCategoryVideo, Width: 640, Height: 360},
+	{Name: "480p", Category: sizeCategoryVideo, Width: 854, Height: 480},
+	{Name: "720p", Category: sizeCategoryVideo, Width: 1280, Height: 720},
+	{Name: "1080p", Category: sizeCategoryVideo, Width: 1920, Height: 1080},
+	{Name: "1440p", Category: sizeCategoryVideo, Width: 2560, Height: 1440},
+	{Name: "4k", Category: sizeCategoryVideo, Width: 3840, Height: 2160},
+	{Name: "8k", Category: sizeCategoryVideo, Width: 7680, Height: 4320},
+	// Device screens (CSS pixels)
+	{Name: "iphone-se", Category: sizeCategoryDevice, Width: 375, Height: 667},
+	{Name: "iphone-14", Category: sizeCategoryDevice, Width: 390, Height: 844},
+	{Name: "iphone-14-pro-max", Category: sizeCategoryDevice, Width: 430, Height: 932},
+	{Name: "pixel-7", Category: sizeCategoryDevice, Width: 412, Height: 915},
+	{Name: "galaxy-s22", Category: sizeCategoryDevice, Width: 360, Height: 780},
+	{Name: "ipad", Category: sizeCategoryDevice, Width: 810, Height: 1080},
+	{Name: "ipad-pro", Category: sizeCategoryDevice, Width: 1024, Height: 1366},
+	{Name: "laptop", Category: sizeCategoryDevice, Width: 1366, Height: 768},
+	{Name: "macbook-air", Category: sizeCategoryDevice, Width: 1440, Height: 900},
+	{Name: "desktop", Category: sizeCategoryDevice, Width: 1920, Height: 1080},
+}
+
+// Mapping of a size alias name to its size
+var sizeAliasLookup = map[string]img.Size{}
+
+func init() {
+	for _, alias := range sizeAliases {
+		sizeAliasLookup[alias.Name] = img.Size{Width: alias.Width, Height: alias.Height}
+	}
+}
+
+// lookupSizeAlias returns the size for alias name and true if it exists otherwise it returns nil, false.
+//
+// The lookup is case-insensitive.
+func lookupSizeAlias(name string) (*img.Size, bool) {
+	size, exists := sizeAliasLookup[strings.ToLower(name)]
+	if !exists {
+		return nil, false
+	}
+	return &img.Size{Width: size.Width, Height: size.Height}, true
+}
+
+// HandlerSizes is a handler to serve the list of size aliases as JSON.
+//
+// The list can be filtered by category with the category query parameter.
+func HandlerSizes(ctx *fiber.Ctx) error {
+	category := ctx.Query("category")
+	if category == "" {
+		return ctx.JSON(sizeAliases)
+	}
+	filtered := []SizeAlias{}
+	for _, alias := range sizeAliases {
+		if alias.Category == category {
+			filtered = append(filtered, alias)
+		}
+	}
+	return ctx.JSON(filtered)
+}
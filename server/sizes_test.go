@@ -0,0 +1,79 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    *img.Size
+		wantErr bool
+	}{
+		{value: "200", want: &img.Size{Width: 200, Height: 200}},
+		{value: "300x200", want: &img.Size{Width: 300, Height: 200}},
+		{value: "300X200", want: &img.Size{Width: 300, Height: 200}},
+		{value: "leaderboard", want: &img.Size{Width: 728, Height: 90}},
+		{value: "MREC", want: &img.Size{Width: 300, Height: 250}},
+		{value: "instagram-story", want: &img.Size{Width: 1080, Height: 1920}},
+		{value: "4k", want: &img.Size{Width: 3840, Height: 2160}},
+		{value: "unknown", wantErr: true},
+		{value: "100+23", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseSize(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.want != nil && *got != *tt.want {
+				t.Errorf("parseSize() = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlerSizes(t *testing.T) {
+	router := fiber.New()
+	registerRoutes(router)
+
+	tests := []struct {
+		route   string
+		wantLen int
+	}{
+		{route: "/sizes", wantLen: len(sizeAliases)},
+		{route: "/sizes?category=video", wantLen: 8},
+		{route: "/sizes?category=unknown", wantLen: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			res, err := router.Test(httptest.NewRequest(http.MethodGet, tt.route, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var aliases []SizeAlias
+			if err := json.NewDecoder(res.Body).Decode(&aliases); err != nil {
+				t.Fatal(err)
+			}
+			if len(aliases) != tt.wantLen {
+				t.Errorf("expected %d aliases, actual %d aliases", tt.wantLen, len(aliases))
+			}
+		})
+	}
+
+	for _, route := range []string{"/png?s=leaderboard", "/leaderboard.png", "/og/jpg"} {
+		res, err := router.Test(httptest.NewRequest(http.MethodGet, route, nil), -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != 200 {
+			t.Errorf("route = %s, expected status code = 200, actual status code = %d", route, res.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/cod3rboy/yaps/utils"
+	"github.com/cod3rboy/yaps/utils/sliceutils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Constant for route parameter key of template name
+const keyTemplate = "template"
+
+// Client Errors for templates
+var (
+	ErrInvalidParamTemplate      = fiber.NewError(fiber.ErrNotFound.Code, "no template found for template ("+keyTemplate+") value")
+	ErrInvalidParamTemplateValue = fiber.NewError(fiber.ErrBadRequest.Code, "invalid template variable value")
+)
+
+// HandlerTemplate is a handler to serve the image of a stored template, see [img.LoadTemplates].
+//
+// Each variable of the template takes its value from the query parameter with the same name, falling back to its
+// default value in the template. Values may be generator keywords and the layers may also use the text variables
+// e.g. {date}. The scale, seed and locale query parameters keep their usual meaning.
+func HandlerTemplate(ctx *fiber.Ctx) error {
+	format := ctx.Params(keyFormat)
+	if !sliceutils.ContainsString(SupportedFormats, format) {
+		return ErrUnsupportedFormat
+	}
+	template := img.FindTemplate(ctx.Params(keyTemplate))
+	if template == nil {
+		return ErrInvalidParamTemplate
+	}
+	scale, err := getParamScale(ctx, presets[defaultPresetName].Scale)
+	if err != nil || scale <= 0 {
+		return ErrInvalidParamScale
+	}
+	randomizer, err := newColorRandomizer(ctx)
+	if err != nil {
+		return ErrInvalidParamScheme
+	}
+	width, height := utils.ScaleDimension(template.Width, scale), utils.ScaleDimension(template.Height, scale)
+	if err := authorizeRender(ctx, format, width, height); err != nil {
+		return err
+	}
+
+	// Values are generated in name order so that a seed always generates the same values
+	names := make([]string, 0, len(template.Variables))
+	for name := range template.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	variables := &textVariables{
+		width:  width,
+		height: height,
+		format: format,
+		scale:  scale,
+		seed:   ctx.Query(keySeed),
+		now:    time.Now(),
+	}
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		value, err := getParamGeneratedText(ctx, ctx.Query(name, template.Variables[name]), randomizer)
+		if err != nil {
+			return err
+		}
+		// Text variables are expanded in the default values of the template but not in the values of the query
+		if ctx.Query(name) == "" {
+			value = variables.expand(value)
+		}
+		values[name] = value
+	}
+	// Template values are inserted as they are, so their braces are never expanded again
+	expand := func(text string) string {
+		return textVariablePattern.ReplaceAllStringFunc(text, func(slot string) string {
+			match := textVariablePattern.FindStringSubmatch(slot)
+			if value, exists := values[match[1]]; exists {
+				return value
+			}
+			if value, known := variables.value(match[1], match[2]); known {
+				return value
+			}
+			return slot
+		})
+	}
+
+	result, err := img.GenerateTemplate(&img.TemplateParams{
+		Format:   format,
+		Template: template,
+		Scale:    scale,
+		Expand:   expand,
+	})
+	if errors.Is(err, img.ErrTemplateValue) {
+		return ErrInvalidParamTemplateValue
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	if !randomizer.cacheable() || !variables.cacheable() {
+		ctx.Set(fiber.HeaderCacheControl, "no-store")
+	}
+	return sendResult(ctx, result)
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cod3rboy/yaps/img"
+	"github.com/gofiber/fiber/v2"
+)
+
+const testTemplate = `
+width: 120
+height: 60
+variables:
+  title: Hello
+  accent: 334155
+layers:
+  - type: fill
+    color: "{accent}"
+  - type: text
+    text: "{title} {w}"
+    size: 12
+    color: ffffff
+`
+
+const testDatedTemplate = `
+width: 120
+height: 60
+variables:
+  updated: "{date:2006}"
+layers:
+  - type: text
+    text: "{updated}"
+    size: 12
+    color: "000000"
+`
+
+func TestHandlerTemplate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "card.yaml"), []byte(testTemplate), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "dated.yaml"), []byte(testDatedTemplate), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := img.LoadTemplates(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	router := fiber.New()
+	registerRoutes(router)
+
+	tests := []struct {
+		route        string
+		statusCode   int
+		cacheControl string
+	}{
+		{route: "/t/card.png", statusCode: 200},
+		{route: "/t/card.jpg?title=Welcome&accent=ff0000&x=2", statusCode: 200},
+		{route: "/t/card.png?title=lorem&seed=1", statusCode: 200},
+		{route: "/t/card.png?title=lorem", statusCode: 200, cacheControl: "no-store"},
+		{route: "/t/card.png?title=%7Bdate%7D", statusCode: 200},
+		{route: "/t/dated.png", statusCode: 200, cacheControl: "no-store"},
+		{route: "/t/card.gif", statusCode: 400},
+		{route: "/t/missing.png", statusCode: 404},
+		{route: "/t/card.png?accent=red", statusCode: 400},
+		{route: "/t/card.png?x=0", statusCode: 400},
+		{route: "/t/card.png?title=lorem&locale=xx", statusCode: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			res, err := router.Test(httptest.NewRequest(http.MethodGet, tt.route, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.statusCode {
+				t.Errorf("expected status code = %d, actual status code = %d", tt.statusCode, res.StatusCode)
+			}
+			if got := res.Header.Get(fiber.HeaderCacheControl); got != tt.cacheControl {
+				t.Errorf("expected cache control = %q, actual cache control = %q", tt.cacheControl, got)
+			}
+		})
+	}
+}
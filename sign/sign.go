@@ -0,0 +1,99 @@
+// Package sign provides functions to sign image urls and to verify the signed urls.
+//
+// The signature is a HMAC-SHA256 digest of the canonical url which is built from the url path
+// and the query parameters sorted by key, excluding the signature parameter itself.
+// An optional expiry timestamp can be included in the signed url which makes it invalid afterwards.
+package sign
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Constants for query parameter keys
+const (
+	KeySignature = "sig"
+	KeyExpiry    = "exp"
+)
+
+// Verification Errors
+var (
+	ErrMissingSignature = errors.New("missing signature")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrInvalidExpiry    = errors.New("invalid expiry")
+	ErrExpired          = errors.New("signature expired")
+)
+
+// Signature returns the url-safe base64 encoded HMAC-SHA256 signature for the given path and query parameters.
+//
+// The signature parameter, if present in query, is not included in the signature.
+func Signature(secret, path string, query url.Values) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(canonical(path, query)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SignURL returns the rawURL with the signature added to its query parameters.
+// If an error occurs while parsing rawURL, it returns "", error.
+//
+// If expiry is not the zero time, it is added to the query parameters as unix timestamp before signing.
+//
+// e.g. For rawURL = https://example.com/yaps/png?s=200x100, the signed url is -
+//
+//	https://example.com/yaps/png?exp=1700000000&s=200x100&sig=<signature>
+func SignURL(secret, rawURL string, expiry time.Time) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Del(KeySignature)
+	if !expiry.IsZero() {
+		query.Set(KeyExpiry, strconv.FormatInt(expiry.Unix(), 10))
+	}
+	query.Set(KeySignature, Signature(secret, u.Path, query))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// Verify checks the signature present in the query parameters against the given path and query parameters.
+//
+// It returns nil when the signature is valid and not expired at time now, otherwise it returns one of
+// [ErrMissingSignature], [ErrInvalidSignature], [ErrInvalidExpiry] or [ErrExpired].
+func Verify(secret, path string, query url.Values, now time.Time) error {
+	signature := query.Get(KeySignature)
+	if signature == "" {
+		return ErrMissingSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(Signature(secret, path, query))) {
+		return ErrInvalidSignature
+	}
+	if expiryValue := query.Get(KeyExpiry); expiryValue != "" {
+		expiry, err := strconv.ParseInt(expiryValue, 10, 64)
+		if err != nil {
+			return ErrInvalidExpiry
+		}
+		if now.Unix() > expiry {
+			return ErrExpired
+		}
+	}
+	return nil
+}
+
+// canonical returns the canonical form of the path and query parameters used for signing.
+//
+// The query parameters are sorted by key and the signature parameter is excluded.
+func canonical(path string, query url.Values) string {
+	values := url.Values{}
+	for key, value := range query {
+		if key != KeySignature {
+			values[key] = value
+		}
+	}
+	return path + "?" + values.Encode()
+}
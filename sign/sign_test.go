@@ -0,0 +1,108 @@
+package sign
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+const testSecret = "s3cr3t"
+
+func TestSignURL(t *testing.T) {
+	expiry := time.Unix(1700000000, 0)
+	signed, err := SignURL(testSecret, "https://example.com/yaps/png?s=200x100&b=FFF", expiry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+	if query.Get(KeyExpiry) != "1700000000" {
+		t.Fatalf("expected expiry = 1700000000, actual expiry = %s", query.Get(KeyExpiry))
+	}
+	if query.Get(KeySignature) == "" {
+		t.Fatal("expected signature in signed url")
+	}
+	if err := Verify(testSecret, u.Path, query, expiry); err != nil {
+		t.Fatalf("expected valid signature, actual error = %v", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	signed := func(rawURL string, expiry time.Time) (string, url.Values) {
+		signedURL, err := SignURL(testSecret, rawURL, expiry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, _ := url.Parse(signedURL)
+		return u.Path, u.Query()
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		query   func() url.Values
+		secret  string
+		wantErr error
+	}{
+		{
+			name:    "Valid signature without expiry",
+			path:    "/png",
+			query:   func() url.Values { _, q := signed("/png?s=100", time.Time{}); return q },
+			secret:  testSecret,
+			wantErr: nil,
+		},
+		{
+			name:    "Valid signature with future expiry",
+			path:    "/png",
+			query:   func() url.Values { _, q := signed("/png?s=100", now.Add(time.Minute)); return q },
+			secret:  testSecret,
+			wantErr: nil,
+		},
+		{
+			name:    "Expired signature",
+			path:    "/png",
+			query:   func() url.Values { _, q := signed("/png?s=100", now.Add(-time.Minute)); return q },
+			secret:  testSecret,
+			wantErr: ErrExpired,
+		},
+		{
+			name:    "Missing signature",
+			path:    "/png",
+			query:   func() url.Values { return url.Values{"s": {"100"}} },
+			secret:  testSecret,
+			wantErr: ErrMissingSignature,
+		},
+		{
+			name:    "Tampered query parameter",
+			path:    "/png",
+			query:   func() url.Values { _, q := signed("/png?s=100", time.Time{}); q.Set("s", "5000"); return q },
+			secret:  testSecret,
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "Tampered path",
+			path:    "/jpg",
+			query:   func() url.Values { _, q := signed("/png?s=100", time.Time{}); return q },
+			secret:  testSecret,
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "Different secret",
+			path:    "/png",
+			query:   func() url.Values { _, q := signed("/png?s=100", time.Time{}); return q },
+			secret:  "other",
+			wantErr: ErrInvalidSignature,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Verify(tt.secret, tt.path, tt.query(), now); err != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 // IsNumber returns true if str is a non-negative numeric string otherwise it returns false.
@@ -53,3 +54,33 @@ func ParseColorHex(colorHexValue string) (uint64, error) {
 	}
 	return color, nil
 }
+
+// Initials returns the uppercase initials of the given name.
+//
+// The initials are the first letters of the first and the last word of the name.
+// Words are separated by any character which is neither a letter nor a digit.
+// If name is an email address, only the part before @ is used.
+//
+// e.g.
+//
+// For name = "Jane Doe", it returns "JD"
+//
+// For name = "jane.van.doe@example.com", it returns "JD"
+//
+// For name = "Jane", it returns "J"
+func Initials(name string) string {
+	if at := strings.Index(name, "@"); at != -1 {
+		name = name[:at]
+	}
+	words := strings.FieldsFunc(name, func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	initials := []rune(words[0])[:1]
+	if len(words) > 1 {
+		initials = append(initials, []rune(words[len(words)-1])[0])
+	}
+	return strings.ToUpper(string(initials))
+}
@@ -125,3 +125,57 @@ func TestParseColorHex(t *testing.T) {
 		})
 	}
 }
+
+func TestInitials(t *testing.T) {
+	type args struct {
+		name string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "First and last name",
+			args: args{"Jane Doe"},
+			want: "JD",
+		},
+		{
+			name: "Middle name",
+			args: args{"jane van doe"},
+			want: "JD",
+		},
+		{
+			name: "Single name",
+			args: args{"Jane"},
+			want: "J",
+		},
+		{
+			name: "Email address",
+			args: args{"jane.doe@example.com"},
+			want: "JD",
+		},
+		{
+			name: "Non-latin name",
+			args: args{"élodie łukasz"},
+			want: "ÉŁ",
+		},
+		{
+			name: "Extra separators",
+			args: args{"  --Jane   Doe!! "},
+			want: "JD",
+		},
+		{
+			name: "Empty name",
+			args: args{""},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Initials(tt.args.name); got != tt.want {
+				t.Errorf("Initials() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}